@@ -1,7 +1,15 @@
 package jira
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,6 +36,30 @@ type Board struct {
 	Name     string `json:"name,omitempty" structs:"name,omitemtpy"`
 	Type     string `json:"type,omitempty" structs:"type,omitempty"`
 	FilterID int    `json:"filterId,omitempty" structs:"filterId,omitempty"`
+	// Location is the project or user the board belongs to. It is only available on JIRA Cloud.
+	Location *BoardLocation `json:"location,omitempty" structs:"location,omitempty"`
+}
+
+// BoardLocation represents the project or user a board belongs to.
+// A location can only be set when a board is created: Type ("project" or "user") and ProjectKeyOrID are sent
+// with CreateBoard. The agile REST API does not support changing the location of an existing board.
+type BoardLocation struct {
+	Type           string `json:"type,omitempty" structs:"type,omitempty"`
+	ProjectKeyOrID string `json:"projectKeyOrId,omitempty" structs:"projectKeyOrId,omitempty"`
+	ProjectID      int    `json:"projectId,omitempty" structs:"projectId,omitempty"`
+	ProjectKey     string `json:"projectKey,omitempty" structs:"projectKey,omitempty"`
+	ProjectName    string `json:"projectName,omitempty" structs:"projectName,omitempty"`
+	ProjectTypeKey string `json:"projectTypeKey,omitempty" structs:"projectTypeKey,omitempty"`
+	UserID         int    `json:"userId,omitempty" structs:"userId,omitempty"`
+	UserAccountID  string `json:"userAccountId,omitempty" structs:"userAccountId,omitempty"`
+	DisplayName    string `json:"displayName,omitempty" structs:"displayName,omitempty"`
+	Name           string `json:"name,omitempty" structs:"name,omitempty"`
+}
+
+// SupportsSprints reports whether the board can have sprints.
+// Only scrum boards have sprints, kanban boards don't.
+func (b *Board) SupportsSprints() bool {
+	return b.Type == "scrum"
 }
 
 // BoardListOptions specifies the optional parameters to the BoardService.GetList
@@ -44,13 +76,69 @@ type BoardListOptions struct {
 	SearchOptions
 }
 
+// IssueListOptions specifies the optional parameters to the methods listing issues of a board or sprint
+type IssueListOptions struct {
+	// JQL filters the returned issues in addition to the board or sprint scope.
+	JQL string `url:"jql,omitempty"`
+	// Fields is the list of fields to return for each issue. By default, all navigable fields are returned.
+	Fields []string `url:"fields,comma,omitempty"`
+	// Properties is the list of entity property keys to return for each issue, use "*all" for all properties.
+	Properties []string `url:"properties,comma,omitempty"`
+	// OrderByRank orders the issues by rank, i.e. in the order of the board, by appending "ORDER BY Rank ASC" to JQL.
+	// Without it, the order of issues is only guaranteed as long as JQL is empty. It is ignored if JQL has an ORDER BY clause.
+	// The order is kept across pages, so collecting all pages yields all issues in rank order.
+	OrderByRank bool `url:"-"`
+	// OrderBy is appended to JQL as its ORDER BY clause, e.g. "updated DESC, key". A stable order keeps paging consistent.
+	// It has to be a comma separated list of fields, each optionally followed by ASC or DESC; a leading "ORDER BY" is allowed.
+	// It takes precedence over OrderByRank. Setting it if JQL has an ORDER BY clause already is an error.
+	OrderBy string `url:"-"`
+
+	SearchOptions
+}
+
 // Wrapper struct for search result
 type sprintsResult struct {
+	StartAt int      `json:"startAt" structs:"startAt"`
+	IsLast  bool     `json:"isLast" structs:"isLast"`
 	Sprints []Sprint `json:"values" structs:"values"`
 }
 
+// boardProjectsResult is only a small wrapper around the projects of a board
+// to be able to parse the results
+type boardProjectsResult struct {
+	StartAt    int       `json:"startAt" structs:"startAt"`
+	MaxResults int       `json:"maxResults" structs:"maxResults"`
+	Total      int       `json:"total" structs:"total"`
+	IsLast     bool      `json:"isLast" structs:"isLast"`
+	Values     []Project `json:"values" structs:"values"`
+}
+
+// QuickFilter represents a quick filter of a board, which narrows the issues shown on the board by JQL
+type QuickFilter struct {
+	ID          int    `json:"id" structs:"id"`
+	BoardID     int    `json:"boardId" structs:"boardId"`
+	Name        string `json:"name" structs:"name"`
+	JQL         string `json:"jql" structs:"jql"`
+	Description string `json:"description,omitempty" structs:"description,omitempty"`
+	Position    int    `json:"position" structs:"position"`
+}
+
+// quickFiltersResult is only a small wrapper around the quick filters of a board
+// to be able to parse the results
+type quickFiltersResult struct {
+	StartAt    int           `json:"startAt" structs:"startAt"`
+	MaxResults int           `json:"maxResults" structs:"maxResults"`
+	Total      int           `json:"total" structs:"total"`
+	IsLast     bool          `json:"isLast" structs:"isLast"`
+	Values     []QuickFilter `json:"values" structs:"values"`
+}
+
 type backlogResults struct {
-	Backlog []Issue `json:"issues" structs:"issues"`
+	StartAt       int     `json:"startAt" structs:"startAt"`
+	MaxResults    int     `json:"maxResults" structs:"maxResults"`
+	Total         int     `json:"total" structs:"total"`
+	NextPageToken string  `json:"nextPageToken" structs:"nextPageToken"`
+	Backlog       []Issue `json:"issues" structs:"issues"`
 }
 
 // Sprint represents a sprint on JIRA agile board
@@ -63,10 +151,46 @@ type Sprint struct {
 	OriginBoardID int        `json:"originBoardId" structs:"originBoardId"`
 	Self          string     `json:"self" structs:"self"`
 	State         string     `json:"state" structs:"state"`
+	Goal          string     `json:"goal,omitempty" structs:"goal,omitempty"`
+}
+
+// MarshalJSON is a custom JSON marshal function for the Sprint struct.
+// JIRA rejects the zero value of time.Time (0001-01-01T00:00:00Z), so
+// unset or zero dates are omitted from the payload entirely.
+func (s Sprint) MarshalJSON() ([]byte, error) {
+	type Alias Sprint
+	return json.Marshal(&struct {
+		CompleteDate *time.Time `json:"completeDate,omitempty"`
+		EndDate      *time.Time `json:"endDate,omitempty"`
+		StartDate    *time.Time `json:"startDate,omitempty"`
+		Alias
+	}{
+		CompleteDate: nonZeroTime(s.CompleteDate),
+		EndDate:      nonZeroTime(s.EndDate),
+		StartDate:    nonZeroTime(s.StartDate),
+		Alias:        Alias(s),
+	})
+}
+
+// nonZeroTime returns t or nil if t points to the zero time.
+func nonZeroTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// SprintIssueCount pairs a sprint with the number of issues it contains
+type SprintIssueCount struct {
+	Sprint
+	IssueCount int
 }
 
 type epicResults struct {
-	Epics []Epic `json:"values" structs:"values"`
+	StartAt    int    `json:"startAt" structs:"startAt"`
+	MaxResults int    `json:"maxResults" structs:"maxResults"`
+	IsLast     bool   `json:"isLast" structs:"isLast"`
+	Epics      []Epic `json:"values" structs:"values"`
 }
 
 type ConfigFilter struct {
@@ -101,6 +225,9 @@ type Estimation struct {
 	Field BoardEstimationField `json:"field" structs:"field"`
 }
 
+// estimationTypeNone is the estimation type of a board without estimation
+const estimationTypeNone = "none"
+
 type Ranking struct {
 	RankCustomFieldId int `json:"rankCustomFieldId" structs:"rankCustomFieldId"`
 }
@@ -115,6 +242,112 @@ type BoardConfiguration struct {
 	Ranking      Ranking      `json:"ranking" structs:"ranking"`
 }
 
+// BoardWithSprint pairs a board with one of its sprints
+type BoardWithSprint struct {
+	Board  Board
+	Sprint Sprint
+}
+
+// BoardHealth reports whether the filter of a board is still usable, see BoardService.CheckBoardHealth.
+type BoardHealth struct {
+	BoardID int
+	// JQL is the query of the filter of the board
+	JQL string
+	// JQLErrors lists the problems JIRA found in JQL, e.g. references to deleted fields or versions
+	JQLErrors []string
+	// Total is the number of issues currently on the board. It is 0 if JQL is invalid.
+	Total int
+}
+
+// Healthy reports whether the JQL of the board is valid and matches at least one issue.
+func (h *BoardHealth) Healthy() bool {
+	return len(h.JQLErrors) == 0 && h.Total > 0
+}
+
+// ColumnWIPStatus reflects the work in progress of a single board column compared to its limits.
+// A Min or Max of 0 means that the column has no such limit.
+type ColumnWIPStatus struct {
+	Column     string
+	IssueCount int
+	Min        int
+	Max        int
+	OverMax    bool
+	UnderMin   bool
+}
+
+// StatusColumnConflict reports a status that is mapped to different columns on different boards.
+// Columns contains the column name of the status, keyed by board Id.
+type StatusColumnConflict struct {
+	StatusID string
+	Columns  map[int]string
+}
+
+// BoardSnapshot captures the state of a board, e.g. as a backup before a migration.
+// It can be serialized to JSON.
+type BoardSnapshot struct {
+	Board         *Board              `json:"board"`
+	Configuration *BoardConfiguration `json:"configuration"`
+	Filter        *Filter             `json:"filter"`
+	// Sprints is empty for boards that do not support sprints
+	Sprints []Sprint `json:"sprints"`
+	// StatusColumns maps every status Id to the name of the column it belongs to
+	StatusColumns map[string]string `json:"statusColumns"`
+}
+
+// BoardOverview combines a board with its configuration and sprints, see GetBoardOverview.
+// If a part could not be fetched, it is nil and its error is set.
+type BoardOverview struct {
+	Board         *Board
+	Configuration *BoardConfiguration
+	// Sprints is empty for boards that do not support sprints
+	Sprints []Sprint
+
+	BoardErr         error
+	ConfigurationErr error
+	SprintsErr       error
+}
+
+// boardAdminsPayload is the request payload of setBoardAdmins
+type boardAdminsPayload struct {
+	ID          int `json:"id"`
+	BoardAdmins struct {
+		UserKeys  []string `json:"userKeys"`
+		GroupKeys []string `json:"groupKeys"`
+	} `json:"boardAdmins"`
+}
+
+// CardLayoutField represents a single field displayed on the cards of a board
+type CardLayoutField struct {
+	ID       int    `json:"id" structs:"id"`
+	FieldID  string `json:"fieldId" structs:"fieldId"`
+	Name     string `json:"name" structs:"name"`
+	Mode     string `json:"mode" structs:"mode"`
+	Position int    `json:"position" structs:"position"`
+}
+
+// CardLayout reflects the fields displayed on the cards of a board.
+// Backlog contains the fields of the backlog (plan mode), Sprint the fields of the active sprint (work mode),
+// both ordered by their position on the card.
+type CardLayout struct {
+	Backlog []CardLayoutField `json:"backlog" structs:"backlog"`
+	Sprint  []CardLayoutField `json:"sprint" structs:"sprint"`
+}
+
+// cardLayoutResult is only a small wrapper around the GetCardLayout method
+// to be able to parse the results
+type cardLayoutResult struct {
+	CardLayoutConfig struct {
+		CurrentFields []CardLayoutField `json:"currentFields"`
+	} `json:"cardLayoutConfig"`
+}
+
+// cardLayoutFieldsByPosition sorts card layout fields by their position
+type cardLayoutFieldsByPosition []CardLayoutField
+
+func (f cardLayoutFieldsByPosition) Len() int           { return len(f) }
+func (f cardLayoutFieldsByPosition) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f cardLayoutFieldsByPosition) Less(i, j int) bool { return f[i].Position < f[j].Position }
+
 // GetAllBoards will returns all boards. This only includes boards that the user has permission to view.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getAllBoards
@@ -135,6 +368,69 @@ func (s *BoardService) GetAllBoards(opt *BoardListOptions) (*BoardsList, *Respon
 	return boards, resp, err
 }
 
+// getAllBoards pages through GetAllBoards, starting at options, until all boards have been collected.
+func (s *BoardService) getAllBoards(options *BoardListOptions) ([]Board, error) {
+	opt := BoardListOptions{}
+	if options != nil {
+		opt = *options
+	}
+
+	var boards []Board
+	err := getAllPages(opt.StartAt, func(startAt int) (int, int, bool, error) {
+		opt.StartAt = startAt
+		list, _, err := s.GetAllBoards(&opt)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		boards = append(boards, list.Values...)
+		return list.StartAt, len(list.Values), list.IsLast, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// GetBoardByName returns the board whose name equals name, ignoring case.
+// The name filter of GetAllBoards also matches boards whose name only contains name, so the results are filtered
+// to boards named name. If several boards only differ in the case of their names, the one matching the case of name is returned.
+// An error is returned if no board or more than one board matches.
+func (s *BoardService) GetBoardByName(name string) (*Board, error) {
+	boards, err := s.getAllBoards(&BoardListOptions{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var exact, folded []Board
+	for _, board := range boards {
+		if board.Name == name {
+			exact = append(exact, board)
+		} else if strings.EqualFold(board.Name, name) {
+			folded = append(folded, board)
+		}
+	}
+	if len(exact) > 0 {
+		return singleBoardNamed(name, exact)
+	}
+	return singleBoardNamed(name, folded)
+}
+
+// singleBoardNamed returns the only board of matches, the boards matching name.
+// An error is returned if there is no board or more than one board.
+func singleBoardNamed(name string, matches []Board) (*Board, error) {
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("No board named %q found", name)
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, board := range matches {
+		ids[i] = strconv.Itoa(board.ID)
+	}
+	return nil, fmt.Errorf("Board name %q is ambiguous, it matches boards %s", name, strings.Join(ids, ", "))
+}
+
 // GetBoard will returns the board for the given boardID.
 // This board will only be returned if the user has permission to view it.
 //
@@ -178,6 +474,74 @@ func (s *BoardService) CreateBoard(board *Board) (*Board, *Response, error) {
 	return responseBoard, resp, nil
 }
 
+// setBoardAdmins replaces the administrators of a board, given a board Id and the user keys of the new admins.
+// The agile REST API does not expose board administrators, so the (private) greenhopper API is used.
+// It identifies users by their user key (User.Key), not by their account Id or name.
+func (s *BoardService) setBoardAdmins(boardID int, adminUserKeys []string) (*Response, error) {
+	apiEndpoint := "rest/greenhopper/1.0/rapidviewconfig/boardadmins"
+	payload := boardAdminsPayload{ID: boardID}
+	payload.BoardAdmins.UserKeys = adminUserKeys
+	payload.BoardAdmins.GroupKeys = []string{}
+
+	req, err := s.client.NewRequest("PUT", apiEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	return resp, err
+}
+
+// CreateBoardWithAdmins creates a new board (see CreateBoard) and makes the given users its administrators.
+// The admins are given by their user key (User.Key), as required by the (private) greenhopper API used to set them.
+// If the admins can not be set, the newly created board is deleted again.
+// Should this rollback fail as well, the returned error says so and the board has to be cleaned up manually.
+func (s *BoardService) CreateBoardWithAdmins(board *Board, adminUserKeys []string) (*Board, *Response, error) {
+	responseBoard, resp, err := s.CreateBoard(board)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp, err = s.setBoardAdmins(responseBoard.ID, adminUserKeys)
+	if err != nil {
+		if _, _, deleteErr := s.DeleteBoard(responseBoard.ID); deleteErr != nil {
+			return nil, resp, fmt.Errorf("Setting the admins of board %d failed: %s. Deleting the board failed as well: %s", responseBoard.ID, err, deleteErr)
+		}
+		return nil, resp, fmt.Errorf("Setting the admins of board %d failed, the board was deleted again: %s", responseBoard.ID, err)
+	}
+
+	return responseBoard, resp, nil
+}
+
+// defaultBoardAdminGroups are the board admin groups used if Client.BoardAdminGroups is empty
+var defaultBoardAdminGroups = []string{"jira-administrators"}
+
+// CanAdministerBoards reports whether the current user is a member of one of the Client.BoardAdminGroups.
+// It is a cheap check before calling methods that require board admin permissions, e.g. CreateBoardWithAdmins.
+// JIRA remains the authority: board admins of individual boards may not be members of these groups.
+func (s *BoardService) CanAdministerBoards() (bool, error) {
+	user, _, err := s.client.User.MyselfWithExpand("groups")
+	if err != nil {
+		return false, err
+	}
+
+	adminGroups := s.client.BoardAdminGroups
+	if len(adminGroups) == 0 {
+		adminGroups = defaultBoardAdminGroups
+	}
+	if user.Groups == nil {
+		return false, nil
+	}
+	for _, group := range user.Groups.Items {
+		for _, adminGroup := range adminGroups {
+			if group.Name == adminGroup {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // GetBoardConfig will return the configuration for a board, given a board Id.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getConfiguration
@@ -193,6 +557,58 @@ func (s *BoardService) GetBoardConfig(boardID string) (*BoardConfiguration, *Res
 	return result, resp, err
 }
 
+// GetCardLayout returns the fields displayed on the cards of a board, for a given board Id.
+// The agile REST API does not expose the card layout, so the board configuration of the (private) greenhopper API is used.
+func (s *BoardService) GetCardLayout(boardID int) (*CardLayout, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/greenhopper/1.0/rapidviewconfig/editmodel.json?rapidViewId=%d&cardLayoutConfig=true", boardID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(cardLayoutResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	layout := new(CardLayout)
+	for _, field := range result.CardLayoutConfig.CurrentFields {
+		switch field.Mode {
+		case "PLAN":
+			layout.Backlog = append(layout.Backlog, field)
+		case "WORK":
+			layout.Sprint = append(layout.Sprint, field)
+		}
+	}
+	sort.Sort(cardLayoutFieldsByPosition(layout.Backlog))
+	sort.Sort(cardLayoutFieldsByPosition(layout.Sprint))
+
+	return layout, resp, nil
+}
+
+// DisableEstimation turns off estimation for a board, given a board Id.
+// The estimation endpoint takes the Id of the new estimation field as "value",
+// "none" is the Id JIRA reports as the estimation type of boards without estimation.
+//
+// JIRA API docs: https://developer.atlassian.com/cloud/jira/software/rest/api-group-board/#api-rest-agile-1-0-board-boardid-estimation-put
+func (s *BoardService) DisableEstimation(boardID int) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/estimation", boardID)
+	payload := struct {
+		Value string `json:"value"`
+	}{
+		Value: estimationTypeNone,
+	}
+
+	req, err := s.client.NewRequest("PUT", apiEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	return resp, err
+}
+
 // DeleteBoard will delete an agile board.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-deleteBoard
@@ -209,10 +625,122 @@ func (s *BoardService) DeleteBoard(boardID int) (*Board, *Response, error) {
 
 // GetAllSprints will returns all sprints from a board, for a given board Id.
 // This only includes sprints that the user has permission to view.
+// The sprints are fetched page by page, the returned Response is the one of the last page.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/sprint
 func (s *BoardService) GetAllSprints(boardID string) ([]Sprint, *Response, error) {
-	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/sprint?maxResults=1000", boardID)
+	return s.getSprints(boardID, "")
+}
+
+// getAllSprints returns all sprints of a board, for a given board Id, fetching page by page until the last page.
+// If state is not empty, only sprints in the given state(s) are returned, e.g. "active" or "future,active".
+func (s *BoardService) getAllSprints(boardID int, state string) ([]Sprint, error) {
+	sprints, _, err := s.getSprints(strconv.Itoa(boardID), state)
+	return sprints, err
+}
+
+// getSprints returns all sprints of a board in the given state(s), like getAllSprints,
+// together with the Response of the last page.
+func (s *BoardService) getSprints(boardID string, state string) ([]Sprint, *Response, error) {
+	opt := struct {
+		State string `url:"state,omitempty"`
+		SearchOptions
+	}{State: state}
+
+	var sprints []Sprint
+	var resp *Response
+	err := getAllPages(0, func(startAt int) (int, int, bool, error) {
+		opt.StartAt = startAt
+		url, err := addOptions(fmt.Sprintf("rest/agile/1.0/board/%s/sprint", boardID), &opt)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		req, err := s.client.NewRequest("GET", url, nil)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		result := new(sprintsResult)
+		resp, err = s.client.Do(req, result)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusBadRequest {
+				err = s.sprintsError(boardID, err)
+			}
+			return 0, 0, false, err
+		}
+		sprints = append(sprints, result.Sprints...)
+		return result.StartAt, len(result.Sprints), result.IsLast, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return sprints, resp, nil
+}
+
+// sprintsError replaces err with a descriptive error if the board, given a board Id, does not support sprints.
+// Otherwise err is returned as it is.
+func (s *BoardService) sprintsError(boardID string, err error) error {
+	id, convErr := strconv.Atoi(boardID)
+	if convErr != nil {
+		return err
+	}
+	board, _, boardErr := s.GetBoard(id)
+	if boardErr != nil || board.SupportsSprints() {
+		return err
+	}
+	return fmt.Errorf("Board %d is a %s board. A kanban board has no sprints", board.ID, board.Type)
+}
+
+// GetSprintDateRange returns the sprint of a board that started first and the sprint that ended last, for a given board Id.
+// The end of a sprint is its complete date or its planned end date, whichever is later.
+// Sprints without the respective dates, e.g. future sprints, are ignored. If no sprint has a date, nil is returned.
+func (s *BoardService) GetSprintDateRange(boardID int) (earliest, latest *Sprint, err error) {
+	sprints, err := s.getAllSprints(boardID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var latestEnd time.Time
+	for i := range sprints {
+		sprint := &sprints[i]
+		if start := nonZeroTime(sprint.StartDate); start != nil {
+			if earliest == nil || start.Before(*earliest.StartDate) {
+				earliest = sprint
+			}
+		}
+		for _, end := range []*time.Time{nonZeroTime(sprint.EndDate), nonZeroTime(sprint.CompleteDate)} {
+			if end != nil && (latest == nil || end.After(latestEnd)) {
+				latest = sprint
+				latestEnd = *end
+			}
+		}
+	}
+	return earliest, latest, nil
+}
+
+// GetStaleSprints returns the active sprints of a board whose end date is before now, for a given board Id.
+// Such sprints were most likely forgotten to be closed. Sprints without an end date are not considered stale.
+func (s *BoardService) GetStaleSprints(boardID int, now time.Time) ([]Sprint, error) {
+	sprints, err := s.getAllSprints(boardID, "active")
+	if err != nil {
+		return nil, err
+	}
+
+	stale := []Sprint{}
+	for _, sprint := range sprints {
+		if sprint.EndDate != nil && sprint.EndDate.Before(now) {
+			stale = append(stale, sprint)
+		}
+	}
+	return stale, nil
+}
+
+// GetActiveSprint returns the active sprint of a board, for a given board Id.
+// If the board has no active sprint, nil is returned. If there are several active sprints, the first one is returned.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/sprint
+func (s *BoardService) GetActiveSprint(boardID int) (*Sprint, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/sprint?state=active", boardID)
 	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
 	if err != nil {
 		return nil, nil, err
@@ -220,39 +748,270 @@ func (s *BoardService) GetAllSprints(boardID string) ([]Sprint, *Response, error
 
 	result := new(sprintsResult)
 	resp, err := s.client.Do(req, result)
-	return result.Sprints, resp, err
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(result.Sprints) == 0 {
+		return nil, resp, nil
+	}
+	return &result.Sprints[0], resp, nil
 }
 
 // GetEpicsForBoard will returns all epics from a board, for a given board Id.
 // This only includes epics that the user has permission to view.
+// The epics are fetched page by page, the returned Response is the one of the last page.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getEpics
 func (s *BoardService) GetEpicsForBoard(boardID string) ([]Epic, *Response, error) {
-	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/epic?maxResults=1000", boardID)
+	return s.getAllEpics(context.Background(), boardID)
+}
+
+// getAllEpics returns all epics from a board, for a given board Id, like GetEpicsForBoard. The requests are bound to ctx.
+func (s *BoardService) getAllEpics(ctx context.Context, boardID string) ([]Epic, *Response, error) {
+	opt := &SearchOptions{}
+	var epics []Epic
+	var resp *Response
+	err := getAllPages(0, func(startAt int) (int, int, bool, error) {
+		opt.StartAt = startAt
+		var result *epicResults
+		var err error
+		if result, resp, err = s.getEpicsPage(ctx, boardID, opt); err != nil {
+			return 0, 0, false, err
+		}
+		epics = append(epics, result.Epics...)
+		return result.StartAt, len(result.Epics), result.IsLast, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return epics, resp, nil
+}
+
+// GetEpicsForBoardWithOptions returns one page of epics of a board, for a given board Id.
+// This only includes epics that the user has permission to view.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getEpics
+func (s *BoardService) GetEpicsForBoardWithOptions(boardID string, options *SearchOptions) ([]Epic, *Response, error) {
+	result, resp, err := s.getEpicsPage(context.Background(), boardID, options)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Epics, resp, nil
+}
+
+// getEpicsPage returns one page of epics of a board, including its paging information. The request is bound to ctx.
+func (s *BoardService) getEpicsPage(ctx context.Context, boardID string, options *SearchOptions) (*epicResults, *Response, error) {
+	apiEndpoint, err := addOptions(fmt.Sprintf("rest/agile/1.0/board/%s/epic", boardID), options)
+	if err != nil {
+		return nil, nil, err
+	}
 	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	result := new(epicResults)
-	resp, err := s.client.Do(req, result)
-	return result.Epics, resp, err
+	resp, err := s.client.Do(req.WithContext(ctx), result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result, resp, nil
 }
 
 // GetIssuesForBacklog will returns all issues on a board's backlog, for a given board Id.
 // This only includes issues that the user has permission to view.
+// The issues are fetched page by page, the returned Response is the one of the last page.
+// Use GetIssuesForBacklogWithOptions to page manually.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getIssuesForBacklog
 func (s *BoardService) GetIssuesForBacklog(boardID string) ([]Issue, *Response, error) {
-	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/backlog?maxResults=1000", boardID)
-	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	var resp *Response
+	issues, err := getAllIssues(nil, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		var result *backlogResults
+		var err error
+		if result, resp, err = s.getBacklogPage(boardID, opt); err != nil {
+			return nil, resp, err
+		}
+		return result.Backlog, resp, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return issues, resp, nil
+}
+
+// GetIssuesForBacklogWithOptions returns one page of issues in the backlog of a board, for a given board Id.
+// This only includes issues that the user has permission to view.
+// Use options.JQL to filter the issues further, e.g. "type = Bug AND resolution = Unresolved", and options.Fields to limit the returned fields.
+// Paging information is available in the returned Response.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getIssuesForBacklog
+func (s *BoardService) GetIssuesForBacklogWithOptions(boardID int, options *IssueListOptions) ([]Issue, *Response, error) {
+	result, resp, err := s.getBacklogPage(strconv.Itoa(boardID), options)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Backlog, resp, nil
+}
+
+// getBacklogPage returns one page of issues in the backlog of a board, including its paging information.
+func (s *BoardService) getBacklogPage(boardID string, options *IssueListOptions) (*backlogResults, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/backlog", boardID)
+	url, err := s.client.issueListURL(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	result := new(backlogResults)
 	resp, err := s.client.Do(req, result)
-	return result.Backlog, resp, err
+	if err != nil {
+		return nil, resp, err
+	}
+	return result, resp, nil
+}
+
+// GetIssuesForBoard returns one page of issues of a board, for a given board Id.
+// This includes issues in the backlog as well as in sprints and only issues that the user has permission to view.
+// Paging information is available in the returned Response.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getIssuesForBoard
+func (s *BoardService) GetIssuesForBoard(boardID int, options *IssueListOptions) ([]Issue, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/issue", boardID)
+	url, err := s.client.issueListURL(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(searchResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Issues, resp, nil
+}
+
+// GetBoardIssuesWithExtraJQL returns all issues of a board, for a given board Id, that also match extraJQL.
+// JIRA applies the board's filter, extraJQL narrows it further, e.g. "labels = urgent".
+// A leading AND is optional. If options contain JQL as well, both clauses are AND-combined.
+// extraJQL has to be a self-contained clause: quotes and parentheses must be balanced and it may not contain an ORDER BY.
+func (s *BoardService) GetBoardIssuesWithExtraJQL(boardID int, extraJQL string, options *IssueListOptions) ([]Issue, error) {
+	extraJQL = NormalizeJQL(extraJQL, false)
+	if len(extraJQL) > 4 && strings.EqualFold(extraJQL[:4], "AND ") {
+		extraJQL = strings.TrimSpace(extraJQL[4:])
+	}
+	if extraJQL == "" {
+		return nil, fmt.Errorf("No extra JQL given")
+	}
+	if err := validateJQLClause(extraJQL); err != nil {
+		return nil, err
+	}
+
+	opt := IssueListOptions{}
+	if options != nil {
+		opt = *options
+	}
+	if opt.JQL = NormalizeJQL(opt.JQL, false); opt.JQL == "" {
+		opt.JQL = "(" + extraJQL + ")"
+	} else {
+		opt.JQL = "(" + opt.JQL + ") AND (" + extraJQL + ")"
+	}
+
+	return getAllIssues(&opt, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+}
+
+// DiscoverMaxResults returns the largest page size JIRA returns for issue lists, using the issues of a board, given a board Id.
+// A page larger than any JIRA instance allows is requested and the page size echoed by JIRA is read.
+// The result is cached by the Client for the issues endpoint of the board and used by subsequent requests to it
+// that neither specify a page size nor have one configured in Client.DefaultPageSizes.
+func (s *BoardService) DiscoverMaxResults(boardID int) (int, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/issue", boardID)
+	if cached := s.client.maxResults.get(apiEndpoint); cached > 0 {
+		return cached, nil
+	}
+
+	_, resp, err := s.GetIssuesForBoard(boardID, &IssueListOptions{
+		Fields:        []string{"key"},
+		SearchOptions: SearchOptions{MaxResults: discoveryMaxResults},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp.MaxResults <= 0 {
+		return 0, fmt.Errorf("No maxResults returned for board %d", boardID)
+	}
+
+	s.client.maxResults.set(apiEndpoint, resp.MaxResults)
+	return resp.MaxResults, nil
+}
+
+// discoveryMaxResults is the page size requested by DiscoverMaxResults
+const discoveryMaxResults = 10000
+
+// getAllPages calls fetch page by page for the paginated lists of the agile API, starting at startAt,
+// until the last page, as flagged by isLast, or an empty page. fetch receives the startAt of the page to fetch
+// and returns the startAt echoed by JIRA and the number of values on the page.
+func getAllPages(startAt int, fetch func(startAt int) (pageStartAt, count int, isLast bool, err error)) error {
+	for {
+		pageStartAt, count, isLast, err := fetch(startAt)
+		if err != nil {
+			return err
+		}
+		if isLast || count == 0 {
+			return nil
+		}
+		startAt = pageStartAt + count
+	}
+}
+
+// getAllIssues calls fetch page by page, starting at options, until all issues have been collected.
+// The next page is computed from the startAt and maxResults echoed by JIRA, not from the requested values,
+// because JIRA may cap maxResults below the requested page size.
+// Endpoints with cursor based paging are detected by the nextPageToken of their responses, which is passed on
+// to the next request until the last page, which has no token. An empty page or a token that was seen before
+// ends the paging as well, so a misbehaving server can not cause an endless loop.
+func getAllIssues(options *IssueListOptions, fetch func(*IssueListOptions) ([]Issue, *Response, error)) ([]Issue, error) {
+	opt := IssueListOptions{}
+	if options != nil {
+		opt = *options
+	}
+
+	var issues []Issue
+	seenTokens := map[string]bool{}
+	for {
+		page, resp, err := fetch(&opt)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page...)
+
+		if resp.NextPageToken != "" || opt.NextPageToken != "" {
+			if resp.NextPageToken == "" || len(page) == 0 || seenTokens[resp.NextPageToken] {
+				return issues, nil
+			}
+			seenTokens[resp.NextPageToken] = true
+			opt.NextPageToken = resp.NextPageToken
+			continue
+		}
+
+		next := resp.StartAt + resp.MaxResults
+		if resp.MaxResults == 0 {
+			next = opt.StartAt + len(page)
+		}
+		if len(page) == 0 || next >= resp.Total {
+			return issues, nil
+		}
+		opt.StartAt = next
+	}
 }
 
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getIssuesForEpic
@@ -268,6 +1027,30 @@ func (s *BoardService) GetIssuesForEpic(boardID string, epicID string) ([]Issue,
 	return result.Backlog, resp, err
 }
 
+// GetIssuesForEpicWithOptions returns one page of issues of an epic on a board, for a given board and epic Id.
+// Use options.JQL to filter the issues further, e.g. "resolution = Unresolved", and options.Fields to limit the returned fields.
+// Paging information is available in the returned Response.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getIssuesForEpic
+func (s *BoardService) GetIssuesForEpicWithOptions(boardID string, epicID string, options *IssueListOptions) ([]Issue, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/epic/%s/issue", boardID, epicID)
+	url, err := s.client.issueListURL(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(backlogResults)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Backlog, resp, nil
+}
+
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getIssuesWithoutEpic
 func (s *BoardService) GetIssuesWithoutEpic(boardID string) ([]Issue, *Response, error) {
 	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%s/epic/none/issue?maxResults=1000", boardID)
@@ -280,3 +1063,904 @@ func (s *BoardService) GetIssuesWithoutEpic(boardID string) ([]Issue, *Response,
 	resp, err := s.client.Do(req, result)
 	return result.Backlog, resp, err
 }
+
+// GetSprintsWithIssueCounts returns all sprints of a board, for a given board Id, together with the number of issues in each sprint.
+// The issue counts are fetched concurrently, with at most concurrency requests in flight; if concurrency is less than 1
+// maxConcurrentRequests is used. Once ctx is done or a request failed, no further requests are started.
+func (s *BoardService) GetSprintsWithIssueCounts(ctx context.Context, boardID int, concurrency int) ([]SprintIssueCount, error) {
+	sprints, _, err := s.GetAllSprints(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SprintIssueCount, len(sprints))
+	err = parallelizeContext(ctx, len(sprints), concurrency, func(ctx context.Context, i int) error {
+		result[i].Sprint = sprints[i]
+		count, _, err := s.client.Sprint.getIssueCount(ctx, sprints[i].ID)
+		result[i].IssueCount = count
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindOrphanedSprintIssues returns issues that are part of an active or future sprint of a board,
+// but are no longer matched by the board's filter (e.g. because the filter was changed after the issue was planned).
+// Closed sprints are not taken into account.
+func (s *BoardService) FindOrphanedSprintIssues(boardID int) ([]Issue, error) {
+	sprints, _, err := s.GetAllSprints(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	boardIssues, err := getAllIssues(&IssueListOptions{Fields: []string{"key"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	onBoard := make(map[string]bool, len(boardIssues))
+	for _, issue := range boardIssues {
+		onBoard[issue.Key] = true
+	}
+
+	var orphans []Issue
+	for _, sprint := range sprints {
+		if sprint.State == "closed" {
+			continue
+		}
+		sprintID := sprint.ID
+		issues, err := getAllIssues(&IssueListOptions{Fields: []string{"key"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+			return s.client.Sprint.GetIssuesForSprintWithOptions(sprintID, opt)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !onBoard[issue.Key] {
+				orphans = append(orphans, issue)
+			}
+		}
+	}
+
+	return orphans, nil
+}
+
+// GetEpics returns the epics of all given boards, for the given board Ids.
+// Epics that appear on several boards are returned only once, in the order they were first seen.
+// At most concurrency boards are queried at the same time. Once ctx is done or a board failed,
+// no further boards are queried and the requests still running are aborted.
+func (s *BoardService) GetEpics(ctx context.Context, boardIDs []int, concurrency int) ([]Epic, error) {
+	epicsPerBoard := make([][]Epic, len(boardIDs))
+	err := parallelizeContext(ctx, len(boardIDs), concurrency, func(ctx context.Context, i int) error {
+		var err error
+		epicsPerBoard[i], _, err = s.getAllEpics(ctx, strconv.Itoa(boardIDs[i]))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	var epics []Epic
+	for _, boardEpics := range epicsPerBoard {
+		for _, epic := range boardEpics {
+			if seen[epic.ID] {
+				continue
+			}
+			seen[epic.ID] = true
+			epics = append(epics, epic)
+		}
+	}
+
+	return epics, nil
+}
+
+// GetWIPStatus returns the work in progress of every column of a board, for a given board Id.
+// Issues are assigned to columns by their status, as configured in the board's column config.
+// Columns with more issues than their max limit are flagged as OverMax, those with less than their min limit as UnderMin.
+// If the board does not use column constraints, no column is flagged.
+func (s *BoardService) GetWIPStatus(boardID int) ([]ColumnWIPStatus, error) {
+	config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := getAllIssues(&IssueListOptions{Fields: []string{"status"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issuesPerStatus := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			issuesPerStatus[issue.Fields.Status.ID]++
+		}
+	}
+
+	constrained := config.ColumnConfig.ConstraintType != "" && config.ColumnConfig.ConstraintType != "none"
+	result := make([]ColumnWIPStatus, len(config.ColumnConfig.Columns))
+	for i, column := range config.ColumnConfig.Columns {
+		status := ColumnWIPStatus{
+			Column: column.Name,
+			Min:    column.Min,
+			Max:    column.Max,
+		}
+		for _, columnStatus := range column.Statuses {
+			status.IssueCount += issuesPerStatus[columnStatus.ID]
+		}
+		if constrained {
+			status.OverMax = column.Max > 0 && status.IssueCount > column.Max
+			status.UnderMin = column.Min > 0 && status.IssueCount < column.Min
+		}
+		result[i] = status
+	}
+
+	return result, nil
+}
+
+// GetBoardsWithActiveSprint returns all boards matching options that currently have an active sprint, paired with that sprint.
+// Kanban boards are skipped, as they have no sprints.
+// At most concurrency boards are queried for their active sprint at the same time.
+func (s *BoardService) GetBoardsWithActiveSprint(options *BoardListOptions, concurrency int) ([]BoardWithSprint, error) {
+	boards, err := s.getAllBoards(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var scrumBoards []Board
+	for _, board := range boards {
+		if board.SupportsSprints() {
+			scrumBoards = append(scrumBoards, board)
+		}
+	}
+
+	sprints := make([]*Sprint, len(scrumBoards))
+	errs := make([]error, len(scrumBoards))
+	parallelize(len(scrumBoards), concurrency, func(i int) {
+		sprints[i], _, errs[i] = s.GetActiveSprint(scrumBoards[i].ID)
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	var result []BoardWithSprint
+	for i, sprint := range sprints {
+		if sprint != nil {
+			result = append(result, BoardWithSprint{Board: scrumBoards[i], Sprint: *sprint})
+		}
+	}
+
+	return result, nil
+}
+
+// GroupBoardsByProject returns all boards matching options, grouped by the keys of the projects they belong to.
+// A board spanning several projects is listed under each of them, boards without a project are omitted.
+// At most concurrency boards are queried for their projects at the same time.
+func (s *BoardService) GroupBoardsByProject(opt *BoardListOptions, concurrency int) (map[string][]Board, error) {
+	boards, err := s.getAllBoards(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([][]Project, len(boards))
+	errs := make([]error, len(boards))
+	parallelize(len(boards), concurrency, func(i int) {
+		projects[i], errs[i] = s.GetProjects(boards[i].ID)
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	result := map[string][]Board{}
+	for i, board := range boards {
+		for _, project := range projects[i] {
+			result[project.Key] = append(result[project.Key], board)
+		}
+	}
+	return result, nil
+}
+
+// ExportBoardIssuesCSV writes all issues of a board, for a given board Id, as CSV to w.
+// The first row is a header containing the column names.
+// Supported columns are "key", "summary", "status", "assignee" and "points" (the estimation field of the board).
+// Every other column is treated as a field Id, e.g. "customfield_10010".
+// Issues are fetched page by page, starting at options.
+func (s *BoardService) ExportBoardIssuesCSV(boardID int, w io.Writer, columns []string, options *IssueListOptions) error {
+	fieldIDs := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "key":
+		case "points":
+			config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+			if err != nil {
+				return err
+			}
+			fieldIDs[i] = config.Estimation.Field.FieldId
+		default:
+			fieldIDs[i] = column
+		}
+	}
+
+	opt := IssueListOptions{}
+	if options != nil {
+		opt = *options
+	}
+	opt.Fields = nil
+	for _, fieldID := range fieldIDs {
+		if fieldID != "" {
+			opt.Fields = append(opt.Fields, fieldID)
+		}
+	}
+
+	issues, err := getAllIssues(&opt, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvValue(issue, column, fieldIDs[i])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// csvValue returns the value of a single CSV column of an issue
+func csvValue(issue Issue, column, fieldID string) string {
+	if column == "key" {
+		return issue.Key
+	}
+	fields := issue.Fields
+	if fields == nil {
+		return ""
+	}
+
+	switch column {
+	case "summary":
+		return fields.Summary
+	case "status":
+		if fields.Status != nil {
+			return fields.Status.Name
+		}
+		return ""
+	case "assignee":
+		if fields.Assignee != nil {
+			return fields.Assignee.DisplayName
+		}
+		return ""
+	}
+
+	value, _ := fields.Unknowns.Value(fieldID)
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		for _, key := range []string{"value", "name", "displayName"} {
+			if inner, ok := v[key]; ok {
+				return fmt.Sprint(inner)
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// estimationFieldID returns the Id of the estimation field of a board configuration.
+// An error is returned if the board does not use estimation.
+func estimationFieldID(config *BoardConfiguration) (string, error) {
+	if config.Estimation.Type == estimationTypeNone || config.Estimation.Field.FieldId == "" {
+		return "", fmt.Errorf("Board %d has no estimation field", config.ID)
+	}
+	return config.Estimation.Field.FieldId, nil
+}
+
+// ResolveEstimationFieldName returns the name of the estimation field of a board configuration, e.g. "Story Points",
+// as listed by FieldService.GetList. The field list is fetched once and cached by the Client,
+// use Client.InvalidateFieldCache to fetch it again.
+// An error is returned if the board does not use estimation or the field does not exist.
+func (s *BoardService) ResolveEstimationFieldName(cfg *BoardConfiguration) (string, error) {
+	fieldID, err := estimationFieldID(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	fields := s.client.fields.get()
+	if fields == nil {
+		fields, _, err = s.client.Field.GetList()
+		if err != nil {
+			return "", err
+		}
+		s.client.fields.set(fields)
+	}
+
+	for _, field := range fields {
+		if field.ID == fieldID {
+			return field.Name, nil
+		}
+	}
+	return "", fmt.Errorf("Estimation field %s of board %d not found", fieldID, cfg.ID)
+}
+
+// Estimation fields of boards that estimate by time. Their values are durations in seconds.
+const (
+	estimationFieldOriginalEstimate  = "timeoriginalestimate"
+	estimationFieldRemainingEstimate = "timeestimate"
+)
+
+// estimationFields returns the fields to request to read the estimation field fieldID of issues.
+// Time estimates are requested together with the time tracking field, which holds them as well.
+func estimationFields(fieldID string) []string {
+	if fieldID == estimationFieldOriginalEstimate || fieldID == estimationFieldRemainingEstimate {
+		return []string{fieldID, "timetracking"}
+	}
+	return []string{fieldID}
+}
+
+// issueEstimate returns the value of the estimation field fieldID of an issue.
+// Time estimates (see estimationFieldOriginalEstimate) are returned in seconds.
+// ok is false if the issue has no estimate.
+func issueEstimate(issue Issue, fieldID string) (estimate float64, ok bool) {
+	fields := issue.Fields
+	if fields == nil {
+		return 0, false
+	}
+
+	var seconds int
+	switch fieldID {
+	case estimationFieldOriginalEstimate:
+		seconds = fields.TimeOriginalEstimate
+		if seconds == 0 && fields.TimeTracking != nil {
+			seconds = fields.TimeTracking.OriginalEstimateSeconds
+		}
+	case estimationFieldRemainingEstimate:
+		seconds = fields.TimeEstimate
+		if seconds == 0 && fields.TimeTracking != nil {
+			seconds = fields.TimeTracking.RemainingEstimateSeconds
+		}
+	default:
+		value, _ := fields.Unknowns.Value(fieldID)
+		estimate, ok = value.(float64)
+		return estimate, ok
+	}
+	return float64(seconds), seconds != 0
+}
+
+// sumEstimates returns the sum of the estimation field fieldID of all issues. Issues without an estimate count as 0.
+// Time estimates are summed in seconds.
+func sumEstimates(issues []Issue, fieldID string) float64 {
+	var sum float64
+	for _, issue := range issues {
+		if estimate, ok := issueEstimate(issue, fieldID); ok {
+			sum += estimate
+		}
+	}
+	return sum
+}
+
+// GetUnestimatedBacklogIssues returns all issues in the backlog of a board that have no estimate, for a given board Id.
+// The estimation field is taken from the board configuration, boards estimating by time are supported as well.
+// Issues with an estimate of 0 are considered unestimated. Only the estimation fields are requested for each issue.
+func (s *BoardService) GetUnestimatedBacklogIssues(boardID int) ([]Issue, error) {
+	issues, fieldID, err := s.getBacklogEstimates(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	unestimated := []Issue{}
+	for _, issue := range issues {
+		if estimate, ok := issueEstimate(issue, fieldID); !ok || estimate == 0 {
+			unestimated = append(unestimated, issue)
+		}
+	}
+	return unestimated, nil
+}
+
+// CountIssuesByAssignee returns the number of issues of a board per assignee, for a given board Id.
+// jql optionally filters the issues, e.g. "statusCategory != Done". Only the assignee field is requested for each issue.
+// Assignees are keyed by account Id, or by user name on JIRA Server where users have no account Id.
+// Unassigned issues are counted under the empty key "".
+func (s *BoardService) CountIssuesByAssignee(boardID int, jql string) (map[string]int, error) {
+	issues, err := getAllIssues(&IssueListOptions{JQL: jql, Fields: []string{"assignee"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		key := ""
+		if issue.Fields != nil && issue.Fields.Assignee != nil {
+			key = issue.Fields.Assignee.AccountID
+			if key == "" {
+				key = issue.Fields.Assignee.Name
+			}
+		}
+		counts[key]++
+	}
+	return counts, nil
+}
+
+// GetEstimationDistribution returns how many issues in the backlog of a board have each estimate, for a given board Id.
+// The estimation field is taken from the board configuration. Time estimates are counted in seconds.
+// Issues without an estimate are not counted.
+func (s *BoardService) GetEstimationDistribution(boardID int) (map[float64]int, error) {
+	issues, fieldID, err := s.getBacklogEstimates(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := make(map[float64]int)
+	for _, issue := range issues {
+		if estimate, ok := issueEstimate(issue, fieldID); ok {
+			distribution[estimate]++
+		}
+	}
+	return distribution, nil
+}
+
+// getBacklogEstimates returns all issues in the backlog of a board with only their estimation fields,
+// and the Id of the estimation field of the board.
+func (s *BoardService) getBacklogEstimates(boardID int) ([]Issue, string, error) {
+	config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, "", err
+	}
+	fieldID, err := estimationFieldID(config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	issues, err := getAllIssues(&IssueListOptions{Fields: estimationFields(fieldID)}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBacklogWithOptions(boardID, opt)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return issues, fieldID, nil
+}
+
+// GetVelocity returns the completed estimates of the last n closed sprints of a board, for a given board Id, and their average.
+// Completed estimates are the sum of the field fieldID of all issues of a sprint in a done status category.
+// If fieldID is empty, the estimation field of the board configuration is used.
+// Sprints are ordered by their complete date, oldest first. If the board has less than n closed sprints, all are used.
+func (s *BoardService) GetVelocity(boardID int, n int, fieldID string) (avg float64, perSprint []float64, err error) {
+	if fieldID == "" {
+		config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+		if err != nil {
+			return 0, nil, err
+		}
+		if fieldID, err = estimationFieldID(config); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	sprints, err := s.getAllSprints(boardID, "closed")
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.Stable(sprintsByCompleteDate(sprints))
+	if n >= 0 && len(sprints) > n {
+		sprints = sprints[len(sprints)-n:]
+	}
+
+	perSprint = make([]float64, len(sprints))
+	errs := make([]error, len(sprints))
+	parallelize(len(sprints), maxConcurrentRequests, func(i int) {
+		opt := &IssueListOptions{JQL: "statusCategory = Done", Fields: estimationFields(fieldID)}
+		issues, err := getAllIssues(opt, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+			return s.client.Sprint.GetIssuesForSprintWithOptions(sprints[i].ID, opt)
+		})
+		perSprint[i], errs[i] = sumEstimates(issues, fieldID), err
+	})
+	if err := firstError(errs); err != nil {
+		return 0, nil, err
+	}
+
+	if len(perSprint) == 0 {
+		return 0, perSprint, nil
+	}
+	var sum float64
+	for _, completed := range perSprint {
+		sum += completed
+	}
+	return sum / float64(len(perSprint)), perSprint, nil
+}
+
+// sprintsByCompleteDate sorts sprints by their complete date, sprints without one first
+type sprintsByCompleteDate []Sprint
+
+func (s sprintsByCompleteDate) Len() int      { return len(s) }
+func (s sprintsByCompleteDate) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s sprintsByCompleteDate) Less(i, j int) bool {
+	a, b := nonZeroTime(s[i].CompleteDate), nonZeroTime(s[j].CompleteDate)
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	return a.Before(*b)
+}
+
+// statusColumns maps every status Id of a board configuration to the name of the column it belongs to
+func statusColumns(config *BoardConfiguration) map[string]string {
+	columns := make(map[string]string)
+	for _, column := range config.ColumnConfig.Columns {
+		for _, status := range column.Statuses {
+			columns[status.ID] = column.Name
+		}
+	}
+	return columns
+}
+
+// getBoardConfigs returns the configurations of all given boards, in the order of the board Ids.
+func (s *BoardService) getBoardConfigs(boardIDs []int) ([]*BoardConfiguration, error) {
+	configs := make([]*BoardConfiguration, len(boardIDs))
+	errs := make([]error, len(boardIDs))
+	parallelize(len(boardIDs), maxConcurrentRequests, func(i int) {
+		configs[i], _, errs[i] = s.GetBoardConfig(strconv.Itoa(boardIDs[i]))
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// BuildGlobalStatusColumnIndex returns a combined status Id to column name index across all given boards.
+// If a status is mapped to different columns on different boards, the column of the first board wins
+// and the status is reported as a conflict. Conflicts are ordered by status Id.
+func (s *BoardService) BuildGlobalStatusColumnIndex(boardIDs []int) (map[string]string, []StatusColumnConflict, error) {
+	configs, err := s.getBoardConfigs(boardIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := make(map[string]string)
+	columnsPerStatus := make(map[string]map[int]string)
+	for i, config := range configs {
+		for statusID, column := range statusColumns(config) {
+			if _, ok := index[statusID]; !ok {
+				index[statusID] = column
+				columnsPerStatus[statusID] = make(map[int]string)
+			}
+			columnsPerStatus[statusID][boardIDs[i]] = column
+		}
+	}
+
+	var conflicting []string
+	for statusID, columns := range columnsPerStatus {
+		for _, column := range columns {
+			if column != index[statusID] {
+				conflicting = append(conflicting, statusID)
+				break
+			}
+		}
+	}
+	sort.Strings(conflicting)
+
+	var conflicts []StatusColumnConflict
+	for _, statusID := range conflicting {
+		conflicts = append(conflicts, StatusColumnConflict{StatusID: statusID, Columns: columnsPerStatus[statusID]})
+	}
+
+	return index, conflicts, nil
+}
+
+// FindDuplicateBoards returns groups of boards that are based on the same filter.
+// Only filters used by more than one board are reported. Boards without a filter Id are skipped.
+// Groups and the boards in each group are ordered as the boards are listed by JIRA.
+func (s *BoardService) FindDuplicateBoards() ([][]Board, error) {
+	boards, err := s.getAllBoards(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	boardIDs := make([]int, len(boards))
+	for i, board := range boards {
+		boardIDs[i] = board.ID
+	}
+	configs, err := s.getBoardConfigs(boardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var filterIDs []string
+	boardsPerFilter := make(map[string][]Board)
+	for i, config := range configs {
+		filterID := config.Filter.ID
+		if filterID == "" {
+			continue
+		}
+		if _, ok := boardsPerFilter[filterID]; !ok {
+			filterIDs = append(filterIDs, filterID)
+		}
+		boardsPerFilter[filterID] = append(boardsPerFilter[filterID], boards[i])
+	}
+
+	duplicates := [][]Board{}
+	for _, filterID := range filterIDs {
+		if len(boardsPerFilter[filterID]) > 1 {
+			duplicates = append(duplicates, boardsPerFilter[filterID])
+		}
+	}
+	return duplicates, nil
+}
+
+// flaggedFieldName is the name of the custom field JIRA Agile uses to flag impediments
+const flaggedFieldName = "Flagged"
+
+// GetFlaggedIssues returns all flagged issues of a board, for a given board Id.
+// The Id of the Flagged custom field is resolved from the field list of the JIRA instance.
+func (s *BoardService) GetFlaggedIssues(boardID int) ([]Issue, error) {
+	fields, _, err := s.client.Field.GetList()
+	if err != nil {
+		return nil, err
+	}
+	jql := ""
+	for _, field := range fields {
+		if field.Custom && field.Name == flaggedFieldName {
+			jql = fmt.Sprintf("cf[%d] is not EMPTY", field.Schema.CustomID)
+			break
+		}
+	}
+	if jql == "" {
+		return nil, fmt.Errorf("No %s field found", flaggedFieldName)
+	}
+
+	return getAllIssues(&IssueListOptions{JQL: jql}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.GetIssuesForBoard(boardID, opt)
+	})
+}
+
+// GetProjects returns all projects associated with a board, for a given board Id.
+// These are the projects referenced by the filter of the board. The projects are fetched page by page.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/project
+func (s *BoardService) GetProjects(boardID int) ([]Project, error) {
+	var projects []Project
+	err := getAllPages(0, func(startAt int) (int, int, bool, error) {
+		url, err := addOptions(fmt.Sprintf("rest/agile/1.0/board/%d/project", boardID), &SearchOptions{StartAt: startAt})
+		if err != nil {
+			return 0, 0, false, err
+		}
+		req, err := s.client.NewRequest("GET", url, nil)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		result := new(boardProjectsResult)
+		if _, err := s.client.Do(req, result); err != nil {
+			return 0, 0, false, err
+		}
+		projects = append(projects, result.Values...)
+		return result.StartAt, len(result.Values), result.IsLast, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetQuickFilters returns all quick filters of a board, for a given board Id, ordered by their position on the board.
+// The quick filters are fetched page by page.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/quickfilter-getAllQuickFilters
+func (s *BoardService) GetQuickFilters(boardID int) ([]QuickFilter, error) {
+	var filters []QuickFilter
+	err := getAllPages(0, func(startAt int) (int, int, bool, error) {
+		url, err := addOptions(fmt.Sprintf("rest/agile/1.0/board/%d/quickfilter", boardID), &SearchOptions{StartAt: startAt})
+		if err != nil {
+			return 0, 0, false, err
+		}
+		req, err := s.client.NewRequest("GET", url, nil)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		result := new(quickFiltersResult)
+		if _, err := s.client.Do(req, result); err != nil {
+			return 0, 0, false, err
+		}
+		filters = append(filters, result.Values...)
+		return result.StartAt, len(result.Values), result.IsLast, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// GetQuickFilter returns a single quick filter of a board, for a given board Id and quick filter Id.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/quickfilter-getQuickFilter
+func (s *BoardService) GetQuickFilter(boardID, filterID int) (*QuickFilter, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/quickfilter/%d", boardID, filterID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := new(QuickFilter)
+	resp, err := s.client.Do(req, filter)
+	if err != nil {
+		return nil, resp, err
+	}
+	return filter, resp, nil
+}
+
+// GetBoardIssueTypes returns the issue types that are valid in the projects of a board, for a given board Id.
+// Issue types shared by several projects are returned only once, in the order they were first seen.
+func (s *BoardService) GetBoardIssueTypes(boardID int) ([]IssueType, error) {
+	projects, err := s.GetProjects(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*Project, len(projects))
+	errs := make([]error, len(projects))
+	parallelize(len(projects), maxConcurrentRequests, func(i int) {
+		details[i], _, errs[i] = s.client.Project.Get(projects[i].ID)
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	issueTypes := []IssueType{}
+	for _, project := range details {
+		for _, issueType := range project.IssueTypes {
+			if seen[issueType.ID] {
+				continue
+			}
+			seen[issueType.ID] = true
+			issueTypes = append(issueTypes, issueType)
+		}
+	}
+	return issueTypes, nil
+}
+
+// CheckBoardHealth validates the JQL of the filter of a board and counts the issues on the board, for a given board Id.
+// A board whose filter references deleted fields or versions silently shows no issues; see BoardHealth.Healthy.
+func (s *BoardService) CheckBoardHealth(boardID int) (*BoardHealth, error) {
+	config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+	filter, _, err := s.client.Filter.Get(config.Filter.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &BoardHealth{BoardID: boardID, JQL: filter.JQL}
+	if health.JQLErrors, _, err = s.client.Filter.ValidateJQL(filter.JQL); err != nil {
+		return nil, err
+	}
+	if len(health.JQLErrors) > 0 {
+		return health, nil
+	}
+
+	_, resp, err := s.GetIssuesForBoard(boardID, &IssueListOptions{
+		Fields:        []string{"key"},
+		SearchOptions: SearchOptions{MaxResults: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	health.Total = resp.Total
+	return health, nil
+}
+
+// SnapshotBoard captures the board, its configuration, filter, sprints and column mapping, for a given board Id.
+func (s *BoardService) SnapshotBoard(boardID int) (*BoardSnapshot, error) {
+	board, _, err := s.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+	filter, _, err := s.client.Filter.Get(config.Filter.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints := []Sprint{}
+	if board.SupportsSprints() {
+		if sprints, err = s.getAllSprints(boardID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return &BoardSnapshot{
+		Board:         board,
+		Configuration: config,
+		Filter:        filter,
+		Sprints:       sprints,
+		StatusColumns: statusColumns(config),
+	}, nil
+}
+
+// GetBoardOverview returns the board, its configuration and all its sprints, for a given board Id.
+// The parts are fetched concurrently. If some of them can not be fetched, the overview contains the other parts
+// and the errors of the failed parts, and an error listing the failed parts is returned along with it.
+func (s *BoardService) GetBoardOverview(boardID int) (*BoardOverview, error) {
+	overview := new(BoardOverview)
+	parallelize(3, 3, func(i int) {
+		switch i {
+		case 0:
+			overview.Board, _, overview.BoardErr = s.GetBoard(boardID)
+		case 1:
+			overview.Configuration, _, overview.ConfigurationErr = s.GetBoardConfig(strconv.Itoa(boardID))
+		case 2:
+			overview.Sprints, overview.SprintsErr = s.getAllSprints(boardID, "")
+		}
+	})
+
+	// Boards without sprints answer the sprint request with an error
+	if overview.Board != nil && !overview.Board.SupportsSprints() {
+		overview.Sprints, overview.SprintsErr = []Sprint{}, nil
+	}
+
+	var failed []string
+	for _, part := range []struct {
+		name string
+		err  error
+	}{{"board", overview.BoardErr}, {"configuration", overview.ConfigurationErr}, {"sprints", overview.SprintsErr}} {
+		if part.err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", part.name, part.err))
+		}
+	}
+	if len(failed) > 0 {
+		return overview, fmt.Errorf("Could not fetch all parts of board %d: %s", boardID, strings.Join(failed, ", "))
+	}
+	return overview, nil
+}
+
+// MapIssuesToColumns returns the board column of every issue in a sprint, keyed by issue key.
+// The column is determined by the status of the issue and the column config of the board.
+// Issues with a status that is not mapped to any column are mapped to an empty string.
+func (s *BoardService) MapIssuesToColumns(boardID, sprintID int) (map[string]string, error) {
+	config, _, err := s.GetBoardConfig(strconv.Itoa(boardID))
+	if err != nil {
+		return nil, err
+	}
+	columns := statusColumns(config)
+
+	issues, err := getAllIssues(&IssueListOptions{Fields: []string{"status"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return s.client.Sprint.GetIssuesForSprintWithOptions(sprintID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		column := ""
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			column = columns[issue.Fields.Status.ID]
+		}
+		result[issue.Key] = column
+	}
+
+	return result, nil
+}
@@ -1,10 +1,19 @@
 package jira
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestBoardService_GetAllBoards(t *testing.T) {
@@ -84,6 +93,30 @@ func TestBoardService_GetBoard(t *testing.T) {
 	}
 }
 
+func TestBoardService_GetBoard_Location(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEdpoint := "/rest/agile/1.0/board/1"
+
+	testMux.HandleFunc(testAPIEdpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEdpoint)
+		fmt.Fprint(w, `{"id":1,"self":"https://test.jira.org/rest/agile/1.0/board/1","name":"Test Weekly","type":"scrum",
+			"location":{"projectId":10000,"displayName":"Test (TEST)","projectName":"Test","projectKey":"TEST","projectTypeKey":"software","name":"Test (TEST)"}}`)
+	})
+
+	board, _, err := testClient.Board.GetBoard(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if board == nil || board.Location == nil {
+		t.Fatalf("Expected board with location. Got %+v", board)
+	}
+	if board.Location.ProjectID != 10000 || board.Location.ProjectKey != "TEST" || board.Location.UserAccountID != "" {
+		t.Errorf("Unexpected board location: %+v", board.Location)
+	}
+}
+
 func TestBoardService_GetBoard_WrongID(t *testing.T) {
 	setup()
 	defer teardown()
@@ -184,3 +217,1921 @@ func TestBoardService_GetAllSprints(t *testing.T) {
 		t.Errorf("Expected 4 transitions. Got %d", len(sprints))
 	}
 }
+
+func TestSprint_MarshalJSON_OmitsUnsetDates(t *testing.T) {
+	zero := time.Time{}
+	sprint := Sprint{
+		Name:      "Sprint 1",
+		State:     "future",
+		StartDate: &zero,
+	}
+
+	b, err := json.Marshal(sprint)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+
+	payload := string(b)
+	for _, key := range []string{"completeDate", "endDate", "startDate"} {
+		if strings.Contains(payload, `"`+key+`"`) {
+			t.Errorf("Expected %q to be omitted. Got %s", key, payload)
+		}
+	}
+	if !strings.Contains(payload, `"name":"Sprint 1"`) {
+		t.Errorf("Expected name in payload. Got %s", payload)
+	}
+}
+
+func TestSprint_MarshalJSON_KeepsSetDates(t *testing.T) {
+	start := time.Date(2017, 6, 1, 9, 0, 0, 0, time.UTC)
+	sprint := &Sprint{
+		Name:      "Sprint 2",
+		StartDate: &start,
+	}
+
+	b, err := json.Marshal(sprint)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+
+	if payload := string(b); !strings.Contains(payload, `"startDate":"2017-06-01T09:00:00Z"`) {
+		t.Errorf("Expected startDate in payload. Got %s", payload)
+	}
+}
+
+func TestBoardService_GetSprintsWithIssueCounts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint")
+		fmt.Fprint(w, `{"maxResults":50,"startAt":0,"isLast":true,"values":[
+			{"id":10,"name":"Sprint 1","state":"closed","originBoardId":1},
+			{"id":11,"name":"Sprint 2","state":"active","originBoardId":1}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/10/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/sprint/10/issue?maxResults=0")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":0,"total":7,"issues":[]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/11/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/sprint/11/issue?maxResults=0")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":0,"total":3,"issues":[]}`)
+	})
+
+	sprints, err := testClient.Board.GetSprintsWithIssueCounts(context.Background(), 1, 2)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(sprints) != 2 {
+		t.Fatalf("Expected 2 sprints. Got %d", len(sprints))
+	}
+	if sprints[0].ID != 10 || sprints[0].IssueCount != 7 {
+		t.Errorf("Expected sprint 10 with 7 issues. Got sprint %d with %d issues", sprints[0].ID, sprints[0].IssueCount)
+	}
+	if sprints[1].ID != 11 || sprints[1].IssueCount != 3 {
+		t.Errorf("Expected sprint 11 with 3 issues. Got sprint %d with %d issues", sprints[1].ID, sprints[1].IssueCount)
+	}
+}
+
+func TestBoardService_GetSprintsWithIssueCounts_Canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":10,"name":"Sprint 1"},{"id":11,"name":"Sprint 2"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no issue counts to be requested. Got %s", r.URL)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := testClient.Board.GetSprintsWithIssueCounts(ctx, 1, 1); err != context.Canceled {
+		t.Errorf("Expected context.Canceled. Got %v", err)
+	}
+}
+
+func TestBoardService_DisableEstimation(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/estimation"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if len(payload) != 1 || payload["value"] != "none" {
+			t.Errorf("Expected payload {\"value\":\"none\"}. Got %v", payload)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Board.DisableEstimation(1); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestBoardService_GetIssuesForBoard(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/issue"
+
+	raw, err := ioutil.ReadFile("./mocks/issues_in_sprint.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?fields=key%2Csummary&jql=labels+%3D+urgent&maxResults=10")
+		fmt.Fprint(w, string(raw))
+	})
+
+	opt := &IssueListOptions{
+		JQL:    "labels = urgent",
+		Fields: []string{"key", "summary"},
+	}
+	opt.MaxResults = 10
+	issues, resp, err := testClient.Board.GetIssuesForBoard(1, opt)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 {
+		t.Errorf("Expected 1 issue. Got %d", len(issues))
+	}
+	if resp.Total != 10 {
+		t.Errorf("Expected total of 10. Got %d", resp.Total)
+	}
+}
+
+func TestBoardService_FindOrphanedSprintIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"isLast":true,"values":[
+			{"id":9,"name":"Sprint 1","state":"closed"},
+			{"id":10,"name":"Sprint 2","state":"active"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":2,"issues":[{"key":"AR-1"},{"key":"AR-3"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/9/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected closed sprint to be skipped")
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/10/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"issues":[{"key":"AR-1"},{"key":"AR-2"}]}`)
+	})
+
+	orphans, err := testClient.Board.FindOrphanedSprintIssues(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(orphans) != 1 || orphans[0].Key != "AR-2" {
+		t.Errorf("Expected orphaned issue AR-2. Got %+v", orphans)
+	}
+}
+
+func TestBoardService_FindOrphanedSprintIssues_Paging(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":10,"name":"Sprint 2","state":"active"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"AR-1"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/10/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if fields := r.URL.Query().Get("fields"); fields != "key" {
+			t.Errorf("Expected only the key field to be requested. Got %q", fields)
+		}
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"issues":[{"key":"AR-1"},{"key":"AR-2"}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/sprint/10/issue?fields=key&startAt=2")
+		fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"issues":[{"key":"AR-3"}]}`)
+	})
+
+	orphans, err := testClient.Board.FindOrphanedSprintIssues(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(orphans) != 2 || orphans[0].Key != "AR-2" || orphans[1].Key != "AR-3" {
+		t.Errorf("Expected orphaned issues AR-2 and AR-3. Got %+v", orphans)
+	}
+}
+
+func TestBoardService_GetEpics_StopsOnError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	requested := map[string]bool{}
+	testMux.HandleFunc("/rest/agile/1.0/board/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		if r.URL.Path == "/rest/agile/1.0/board/1/epic" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+	})
+
+	_, err := testClient.Board.GetEpics(context.Background(), []int{1, 2, 3}, 1)
+	if jiraErr, ok := err.(*Error); !ok || jiraErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected the 404 of board 1. Got %v", err)
+	}
+	if requested["/rest/agile/1.0/board/2/epic"] || requested["/rest/agile/1.0/board/3/epic"] {
+		t.Errorf("Expected no further boards to be queried after the error. Got %v", requested)
+	}
+}
+
+func TestBoardService_GetEpics(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/epic", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":100,"key":"AR-1","name":"Shared"},{"id":101,"key":"AR-2","name":"Only one"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/2/epic", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":100,"key":"AR-1","name":"Shared"},{"id":200,"key":"BR-1","name":"Only two"}]}`)
+	})
+
+	epics, err := testClient.Board.GetEpics(context.Background(), []int{1, 2}, 2)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(epics) != 3 {
+		t.Fatalf("Expected 3 epics. Got %d", len(epics))
+	}
+	for i, want := range []int{100, 101, 200} {
+		if epics[i].ID != want {
+			t.Errorf("Expected epic %d at position %d. Got %d", want, i, epics[i].ID)
+		}
+	}
+}
+
+func TestBoardService_GetCardLayout(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapidviewconfig/editmodel.json"
+
+	raw, err := ioutil.ReadFile("./mocks/card_layout.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?rapidViewId=1&cardLayoutConfig=true")
+		fmt.Fprint(w, string(raw))
+	})
+
+	layout, _, err := testClient.Board.GetCardLayout(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if layout == nil {
+		t.Fatal("Expected card layout. Card layout is nil")
+	}
+	if len(layout.Backlog) != 2 {
+		t.Fatalf("Expected 2 backlog fields. Got %d", len(layout.Backlog))
+	}
+	if layout.Backlog[0].FieldID != "priority" || layout.Backlog[1].FieldID != "customfield_10002" {
+		t.Errorf("Expected backlog fields ordered by position. Got %+v", layout.Backlog)
+	}
+	if len(layout.Sprint) != 1 || layout.Sprint[0].FieldID != "assignee" {
+		t.Errorf("Expected assignee as only sprint field. Got %+v", layout.Sprint)
+	}
+}
+
+func TestBoardService_CreateBoardWithAdmins(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":17,"self":"https://test.jira.org/rest/agile/1.0/board/17","name":"Test","type":"kanban"}`)
+	})
+	testMux.HandleFunc("/rest/greenhopper/1.0/rapidviewconfig/boardadmins", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		var payload boardAdminsPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if payload.ID != 17 || len(payload.BoardAdmins.UserKeys) != 1 || payload.BoardAdmins.UserKeys[0] != "admin" {
+			t.Errorf("Expected admin to be set on board 17. Got %+v", payload)
+		}
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/17", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected board not to be deleted")
+	})
+
+	board, _, err := testClient.Board.CreateBoardWithAdmins(&Board{Name: "Test", Type: "kanban", FilterID: 17}, []string{"admin"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if board == nil || board.ID != 17 {
+		t.Errorf("Expected board 17. Got %+v", board)
+	}
+	if want := []string{"POST /rest/agile/1.0/board", "PUT /rest/greenhopper/1.0/rapidviewconfig/boardadmins"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v. Got %v", want, calls)
+	}
+}
+
+func TestBoardService_CreateBoardWithAdmins_Rollback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted := false
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":17,"name":"Test","type":"kanban"}`)
+	})
+	testMux.HandleFunc("/rest/greenhopper/1.0/rapidviewconfig/boardadmins", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/17", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	board, _, err := testClient.Board.CreateBoardWithAdmins(&Board{Name: "Test", Type: "kanban", FilterID: 17}, []string{"admin"})
+	if err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if board != nil {
+		t.Errorf("Expected no board. Got %+v", board)
+	}
+	if !deleted {
+		t.Error("Expected the created board to be deleted")
+	}
+}
+
+func TestBoard_SupportsSprints(t *testing.T) {
+	if b := (&Board{Type: "scrum"}); !b.SupportsSprints() {
+		t.Error("Expected scrum board to support sprints")
+	}
+	if b := (&Board{Type: "kanban"}); b.SupportsSprints() {
+		t.Error("Expected kanban board not to support sprints")
+	}
+}
+
+func TestBoardService_GetAllSprints_KanbanBoard(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/5/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errorMessages":["The board does not support sprints"],"errors":{}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/5", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":5,"name":"Test Production Support","type":"kanban"}`)
+	})
+
+	_, _, err := testClient.Board.GetAllSprints("5")
+	if err == nil {
+		t.Fatal("Expected an error. Got none")
+	}
+	if !strings.Contains(err.Error(), "kanban board has no sprints") {
+		t.Errorf("Expected a kanban error. Got %s", err)
+	}
+}
+
+func TestGetAllIssues_ServerCapsMaxResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const total, serverMax = 120, 50
+	var startAts []string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("maxResults"); got != "100" {
+			t.Errorf("Expected maxResults 100 to be requested. Got %s", got)
+		}
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		startAts = append(startAts, strconv.Itoa(startAt))
+
+		var keys []string
+		for i := startAt; i < total && i < startAt+serverMax; i++ {
+			keys = append(keys, fmt.Sprintf(`{"key":"AR-%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"startAt":%d,"maxResults":%d,"total":%d,"issues":[%s]}`, startAt, serverMax, total, strings.Join(keys, ","))
+	})
+
+	opt := &IssueListOptions{}
+	opt.MaxResults = 100
+	issues, err := getAllIssues(opt, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return testClient.Board.GetIssuesForBoard(1, opt)
+	})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != total {
+		t.Errorf("Expected %d issues. Got %d", total, len(issues))
+	}
+	for i, issue := range issues {
+		if want := fmt.Sprintf("AR-%d", i); issue.Key != want {
+			t.Errorf("Expected issue %s at position %d. Got %s", want, i, issue.Key)
+			break
+		}
+	}
+	if want := []string{"0", "50", "100"}; !reflect.DeepEqual(startAts, want) {
+		t.Errorf("Expected pages starting at %v. Got %v", want, startAts)
+	}
+}
+
+func TestBoardService_GetWIPStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"Test","columnConfig":{"constraintType":"issueCount","columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},
+			{"name":"In Progress","statuses":[{"id":"3"},{"id":"4"}],"max":2},
+			{"name":"Review","statuses":[{"id":"5"}],"min":1},
+			{"name":"Done","statuses":[{"id":"6"}]}]}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/issue?fields=status")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":5,"issues":[
+			{"key":"AR-1","fields":{"status":{"id":"1"}}},
+			{"key":"AR-2","fields":{"status":{"id":"3"}}},
+			{"key":"AR-3","fields":{"status":{"id":"3"}}},
+			{"key":"AR-4","fields":{"status":{"id":"4"}}},
+			{"key":"AR-5","fields":{"status":{"id":"6"}}}]}`)
+	})
+
+	columns, err := testClient.Board.GetWIPStatus(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(columns) != 4 {
+		t.Fatalf("Expected 4 columns. Got %d", len(columns))
+	}
+
+	inProgress := columns[1]
+	if inProgress.IssueCount != 3 || !inProgress.OverMax || inProgress.UnderMin {
+		t.Errorf("Expected In Progress with 3 issues over its max. Got %+v", inProgress)
+	}
+	review := columns[2]
+	if review.IssueCount != 0 || !review.UnderMin || review.OverMax {
+		t.Errorf("Expected Review with 0 issues under its min. Got %+v", review)
+	}
+	for _, column := range []ColumnWIPStatus{columns[0], columns[3]} {
+		if column.OverMax || column.UnderMin {
+			t.Errorf("Expected column %s without violation. Got %+v", column.Column, column)
+		}
+	}
+}
+
+func TestBoardService_GetActiveSprint(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/sprint"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?state=active")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":11,"name":"Sprint 2","state":"active"}]}`)
+	})
+
+	sprint, _, err := testClient.Board.GetActiveSprint(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if sprint == nil || sprint.ID != 11 {
+		t.Errorf("Expected active sprint 11. Got %+v", sprint)
+	}
+}
+
+func TestBoardService_GetBoardsWithActiveSprint(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":0,"isLast":true,"values":[
+			{"id":1,"name":"Active","type":"scrum"},
+			{"id":2,"name":"Idle","type":"scrum"},
+			{"id":3,"name":"Flow","type":"kanban"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint?state=active")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":11,"name":"Sprint 2","state":"active"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/2/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testRequestURL(t, r, "/rest/agile/1.0/board/2/sprint?state=active")
+		fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/3/sprint", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected kanban board to be skipped")
+	})
+
+	boards, err := testClient.Board.GetBoardsWithActiveSprint(nil, 2)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(boards) != 1 {
+		t.Fatalf("Expected 1 board. Got %d", len(boards))
+	}
+	if boards[0].Board.ID != 1 || boards[0].Sprint.ID != 11 {
+		t.Errorf("Expected board 1 with sprint 11. Got %+v", boards[0])
+	}
+}
+
+func TestBoardService_ExportBoardIssuesCSV(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"estimation":{"type":"field","field":{"fieldId":"customfield_10002","displayName":"Story Points"}}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/issue?fields=summary%2Cstatus%2Cassignee%2Ccustomfield_10002%2Ccustomfield_10010")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":2,"issues":[
+			{"key":"AR-1","fields":{"summary":"Login, \"remember me\"","status":{"name":"Done"},"assignee":{"displayName":"Fred F. User"},"customfield_10002":3,"customfield_10010":{"value":"Team A"}}},
+			{"key":"AR-2","fields":{"summary":"Logout","status":{"name":"To Do"},"customfield_10002":null}}]}`)
+	})
+
+	var buf bytes.Buffer
+	columns := []string{"key", "summary", "status", "assignee", "points", "customfield_10010"}
+	if err := testClient.Board.ExportBoardIssuesCSV(1, &buf, columns, nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+
+	want := "key,summary,status,assignee,points,customfield_10010\n" +
+		"AR-1,\"Login, \"\"remember me\"\"\",Done,Fred F. User,3,Team A\n" +
+		"AR-2,Logout,To Do,,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSV is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBoardService_GetBoardConfig_ETagCache(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/configuration"
+
+	requests := 0
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"id":1,"name":"Test","estimation":{"type":"field","field":{"fieldId":"customfield_10002","displayName":"Story Points"}}}`)
+	})
+
+	testClient.EnableETagCache()
+	if _, _, err := testClient.Board.GetBoardConfig("1"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+
+	config, resp, err := testClient.Board.GetBoardConfig("1")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected status 304. Got %d", resp.StatusCode)
+	}
+	if config.Name != "Test" || config.Estimation.Field.FieldId != "customfield_10002" {
+		t.Errorf("Expected cached board config. Got %+v", config)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests. Got %d", requests)
+	}
+}
+
+func TestBoardService_MapIssuesToColumns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"columnConfig":{"columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},
+			{"name":"In Progress","statuses":[{"id":"3"},{"id":"4"}]},
+			{"name":"Done","statuses":[{"id":"6"}]}]}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/10/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"issues":[
+			{"key":"AR-1","fields":{"status":{"id":"1"}}},
+			{"key":"AR-2","fields":{"status":{"id":"4"}}},
+			{"key":"AR-3","fields":{"status":{"id":"10100"}}}]}`)
+	})
+
+	columns, err := testClient.Board.MapIssuesToColumns(1, 10)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	want := map[string]string{"AR-1": "To Do", "AR-2": "In Progress", "AR-3": ""}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("Expected columns %v. Got %v", want, columns)
+	}
+}
+
+func TestBoardService_MapIssuesToColumns_Paging(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"columnConfig":{"columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},
+			{"name":"Done","statuses":[{"id":"6"}]}]}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/10/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if fields := r.URL.Query().Get("fields"); fields != "status" {
+			t.Errorf("Expected only the status field to be requested. Got %q", fields)
+		}
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":2,"issues":[{"key":"AR-1","fields":{"status":{"id":"1"}}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt":1,"maxResults":1,"total":2,"issues":[{"key":"AR-2","fields":{"status":{"id":"6"}}}]}`)
+	})
+
+	columns, err := testClient.Board.MapIssuesToColumns(1, 10)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	want := map[string]string{"AR-1": "To Do", "AR-2": "Done"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("Expected columns %v. Got %v", want, columns)
+	}
+}
+
+func TestBoardService_BuildGlobalStatusColumnIndex(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"columnConfig":{"columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},
+			{"name":"In Progress","statuses":[{"id":"3"}]},
+			{"name":"Done","statuses":[{"id":"10001"}]}]}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/2/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":2,"columnConfig":{"columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},
+			{"name":"Review","statuses":[{"id":"3"}]},
+			{"name":"Done","statuses":[{"id":"10001"},{"id":"10002"}]}]}}`)
+	})
+
+	index, conflicts, err := testClient.Board.BuildGlobalStatusColumnIndex([]int{1, 2})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	want := map[string]string{"1": "To Do", "3": "In Progress", "10001": "Done", "10002": "Done"}
+	if !reflect.DeepEqual(index, want) {
+		t.Errorf("Expected index %v. Got %v", want, index)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict. Got %d", len(conflicts))
+	}
+	if conflicts[0].StatusID != "3" || conflicts[0].Columns[1] != "In Progress" || conflicts[0].Columns[2] != "Review" {
+		t.Errorf("Unexpected conflict: %+v", conflicts[0])
+	}
+}
+
+func TestBoardService_GetUnestimatedBacklogIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"estimation":{"type":"field","field":{"fieldId":"customfield_10002","displayName":"Story Points"}}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/backlog?fields=customfield_10002")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":4,"issues":[
+			{"key":"TEST-1","fields":{"customfield_10002":3}},
+			{"key":"TEST-2","fields":{"customfield_10002":null}},
+			{"key":"TEST-3","fields":{"customfield_10002":0}},
+			{"key":"TEST-4","fields":{"customfield_10002":5}}]}`)
+	})
+
+	issues, err := testClient.Board.GetUnestimatedBacklogIssues(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	if want := []string{"TEST-2", "TEST-3"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected unestimated issues %v. Got %v", want, keys)
+	}
+}
+
+func TestBoardService_FindDuplicateBoards(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":1,"name":"Team A"},{"id":2,"name":"Team B"},{"id":3,"name":"Team A (copy)"}]}`)
+	})
+	for boardID, filterID := range map[int]string{1: "10000", 2: "10001", 3: "10000"} {
+		boardID, filterID := boardID, filterID
+		testMux.HandleFunc(fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID), func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprintf(w, `{"id":%d,"filter":{"id":"%s"}}`, boardID, filterID)
+		})
+	}
+
+	duplicates, err := testClient.Board.FindDuplicateBoards()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected 1 group of duplicates. Got %d", len(duplicates))
+	}
+	if len(duplicates[0]) != 2 || duplicates[0][0].ID != 1 || duplicates[0][1].ID != 3 {
+		t.Errorf("Expected boards 1 and 3 to be duplicates. Got %+v", duplicates[0])
+	}
+}
+
+func TestBoardService_GetFlaggedIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"flagged","name":"Flagged","custom":false},
+			{"id":"customfield_10021","name":"Flagged","custom":true,"schema":{"type":"array","customId":10021}}]`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql := r.URL.Query().Get("jql"); jql != "cf[10021] is not EMPTY" {
+			t.Errorf("Unexpected JQL: %q", jql)
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TEST-1"}]}`)
+	})
+
+	issues, err := testClient.Board.GetFlaggedIssues(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "TEST-1" {
+		t.Errorf("Expected flagged issue TEST-1. Got %+v", issues)
+	}
+}
+
+func TestBoardService_GetBoardIssueTypes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/project", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"maxResults":50,"startAt":0,"total":2,"isLast":true,"values":[
+			{"id":"10000","key":"AR","name":"Alpha"},{"id":"10001","key":"BR","name":"Beta"}]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/project/10000", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":"10000","key":"AR","issueTypes":[{"id":"1","name":"Bug"},{"id":"3","name":"Task"}]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/project/10001", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":"10001","key":"BR","issueTypes":[{"id":"3","name":"Task"},{"id":"10001","name":"Story"}]}`)
+	})
+
+	issueTypes, err := testClient.Board.GetBoardIssueTypes(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	var names []string
+	for _, issueType := range issueTypes {
+		names = append(names, issueType.Name)
+	}
+	if want := []string{"Bug", "Task", "Story"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected issue types %v. Got %v", want, names)
+	}
+}
+
+func TestBoardService_GetSprintDateRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"startAt":0,"isLast":false,"values":[
+				{"id":1,"state":"closed","startDate":"2017-01-02T10:00:00.000Z","endDate":"2017-01-16T10:00:00.000Z","completeDate":"2017-01-17T10:00:00.000Z"},
+				{"id":2,"state":"closed","startDate":"2016-12-19T10:00:00.000Z","endDate":"2017-01-02T10:00:00.000Z","completeDate":null}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint?startAt=2")
+		fmt.Fprint(w, `{"startAt":2,"isLast":true,"values":[
+			{"id":3,"state":"active","startDate":"2017-01-17T10:00:00.000Z","endDate":"2017-01-31T10:00:00.000Z"},
+			{"id":4,"state":"future"}]}`)
+	})
+
+	earliest, latest, err := testClient.Board.GetSprintDateRange(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if earliest == nil || earliest.ID != 2 {
+		t.Errorf("Expected sprint 2 to be the earliest. Got %+v", earliest)
+	}
+	if latest == nil || latest.ID != 3 {
+		t.Errorf("Expected sprint 3 to be the latest. Got %+v", latest)
+	}
+}
+
+func TestBoardService_GetBoardIssuesWithExtraJQL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql, want := r.URL.Query().Get("jql"), `(type = Bug) AND (labels = urgent OR summary ~ "(urgent")`; jql != want {
+			t.Errorf("Expected JQL %q. Got %q", want, jql)
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TEST-1"}]}`)
+	})
+
+	issues, err := testClient.Board.GetBoardIssuesWithExtraJQL(1, ` AND labels = urgent OR summary ~ "(urgent"`, &IssueListOptions{JQL: "type = Bug"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 {
+		t.Errorf("Expected 1 issue. Got %d", len(issues))
+	}
+
+	for _, invalid := range []string{"", "labels = urgent)", `summary ~ "urgent`, "labels = urgent ORDER BY rank"} {
+		if _, err := testClient.Board.GetBoardIssuesWithExtraJQL(1, invalid, nil); err == nil {
+			t.Errorf("Expected an error for JQL %q. Got none", invalid)
+		}
+	}
+}
+
+func TestSumEstimates_TimeTracking(t *testing.T) {
+	issues := []Issue{
+		{Key: "TEST-1", Fields: &IssueFields{TimeOriginalEstimate: 7200}},
+		{Key: "TEST-2", Fields: &IssueFields{TimeTracking: &TimeTracking{OriginalEstimate: "1h", OriginalEstimateSeconds: 3600}}},
+		{Key: "TEST-3", Fields: &IssueFields{}},
+	}
+
+	if sum := sumEstimates(issues, "timeoriginalestimate"); sum != 10800 {
+		t.Errorf("Expected 10800 seconds. Got %v", sum)
+	}
+	if _, ok := issueEstimate(issues[2], "timeoriginalestimate"); ok {
+		t.Error("Expected TEST-3 to have no estimate")
+	}
+}
+
+func TestBoardService_GetUnestimatedBacklogIssues_TimeTracking(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"estimation":{"type":"field","field":{"fieldId":"timeoriginalestimate","displayName":"Original Time Estimate"}}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/backlog?fields=timeoriginalestimate%2Ctimetracking")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":2,"issues":[
+			{"key":"TEST-1","fields":{"timeoriginalestimate":7200,"timetracking":{"originalEstimate":"2h","originalEstimateSeconds":7200}}},
+			{"key":"TEST-2","fields":{"timeoriginalestimate":null,"timetracking":{}}}]}`)
+	})
+
+	issues, err := testClient.Board.GetUnestimatedBacklogIssues(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "TEST-2" {
+		t.Errorf("Expected TEST-2 to be unestimated. Got %+v", issues)
+	}
+}
+
+func TestBoardService_GetIssuesForBoard_Properties(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/issue?fields=summary&properties=integration.sync")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TEST-1","fields":{"summary":"Log in"},
+			"properties":{"integration.sync":{"externalId":"abc-123","revision":4}}}]}`)
+	})
+
+	issues, _, err := testClient.Board.GetIssuesForBoard(1, &IssueListOptions{Fields: []string{"summary"}, Properties: []string{"integration.sync"}})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue. Got %d", len(issues))
+	}
+	property, ok := issues[0].Properties["integration.sync"].(map[string]interface{})
+	if !ok || property["externalId"] != "abc-123" || property["revision"] != float64(4) {
+		t.Errorf("Unexpected issue properties: %+v", issues[0].Properties)
+	}
+}
+
+func TestBoardService_GetVelocity(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint?state=closed")
+		fmt.Fprint(w, `{"startAt":0,"isLast":true,"values":[
+			{"id":4,"state":"closed","completeDate":"2017-02-14T10:00:00.000Z"},
+			{"id":1,"state":"closed","completeDate":"2017-01-03T10:00:00.000Z"},
+			{"id":2,"state":"closed","completeDate":"2017-01-17T10:00:00.000Z"},
+			{"id":3,"state":"closed","completeDate":"2017-01-31T10:00:00.000Z"}]}`)
+	})
+	for sprintID, points := range map[int]string{2: "3,5", 3: "8", 4: "1,2,4"} {
+		points := points
+		testMux.HandleFunc(fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID), func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			if jql := r.URL.Query().Get("jql"); jql != "statusCategory = Done" {
+				t.Errorf("Unexpected JQL: %q", jql)
+			}
+			var issues []string
+			for i, p := range strings.Split(points, ",") {
+				issues = append(issues, fmt.Sprintf(`{"key":"TEST-%d","fields":{"customfield_10002":%s}}`, i, p))
+			}
+			fmt.Fprintf(w, `{"startAt":0,"maxResults":50,"total":%d,"issues":[%s]}`, len(issues), strings.Join(issues, ","))
+		})
+	}
+
+	avg, perSprint, err := testClient.Board.GetVelocity(1, 3, "customfield_10002")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if want := []float64{8, 8, 7}; !reflect.DeepEqual(perSprint, want) {
+		t.Errorf("Expected completed points %v. Got %v", want, perSprint)
+	}
+	if want := 23.0 / 3; avg != want {
+		t.Errorf("Expected average %v. Got %v", want, avg)
+	}
+}
+
+func TestBoardService_DiscoverMaxResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requested []string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		requested = append(requested, r.URL.Query().Get("maxResults"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		if maxResults == 0 || maxResults > 100 {
+			maxResults = 100
+		}
+		fmt.Fprintf(w, `{"startAt":0,"maxResults":%d,"total":0,"issues":[]}`, maxResults)
+	})
+
+	maxResults, err := testClient.Board.DiscoverMaxResults(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if maxResults != 100 {
+		t.Errorf("Expected maxResults 100. Got %d", maxResults)
+	}
+	if cached, _ := testClient.Board.DiscoverMaxResults(1); cached != 100 {
+		t.Errorf("Expected cached maxResults 100. Got %d", cached)
+	}
+
+	testClient.Board.GetIssuesForBoard(1, nil)
+	if want := []string{"10000", "100"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("Expected requested page sizes %v. Got %v", want, requested)
+	}
+
+	// The discovered page size only applies to the endpoint it was discovered for
+	testMux.HandleFunc("/rest/agile/1.0/sprint/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		if maxResults := r.URL.Query().Get("maxResults"); maxResults != "" {
+			t.Errorf("Expected no maxResults for the issues of a sprint. Got %q", maxResults)
+		}
+		fmt.Fprint(w, `{"issues":[]}`)
+	})
+	if _, _, err := testClient.Sprint.GetIssuesForSprintWithOptions(2, nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestBoardService_SnapshotBoard(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"Team A","type":"scrum"}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"Team A","filter":{"id":"10000"},"columnConfig":{"columns":[
+			{"name":"To Do","statuses":[{"id":"1"}]},{"name":"Done","statuses":[{"id":"10001"}]}]}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/filter/10000", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":"10000","name":"Filter for Team A","jql":"project = TEST ORDER BY Rank ASC"}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":0,"isLast":true,"values":[{"id":1,"name":"Sprint 1","state":"closed"},{"id":2,"name":"Sprint 2","state":"active"}]}`)
+	})
+
+	snapshot, err := testClient.Board.SnapshotBoard(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if snapshot.Board.Name != "Team A" || snapshot.Filter.JQL != "project = TEST ORDER BY Rank ASC" || len(snapshot.Sprints) != 2 {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	if want := map[string]string{"1": "To Do", "10001": "Done"}; !reflect.DeepEqual(snapshot.StatusColumns, want) {
+		t.Errorf("Expected status columns %v. Got %v", want, snapshot.StatusColumns)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Could not serialize snapshot: %s", err)
+	}
+	var restored BoardSnapshot
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Could not deserialize snapshot: %s", err)
+	}
+	if restored.Configuration.Filter.ID != "10000" || restored.Sprints[1].State != "active" {
+		t.Errorf("Unexpected restored snapshot: %+v", restored)
+	}
+}
+
+func TestBoardService_GetEstimationDistribution(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"estimation":{"type":"field","field":{"fieldId":"customfield_10002","displayName":"Story Points"}}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/backlog?fields=customfield_10002")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":6,"issues":[
+			{"key":"TEST-1","fields":{"customfield_10002":3}},
+			{"key":"TEST-2","fields":{"customfield_10002":null}},
+			{"key":"TEST-3","fields":{"customfield_10002":5}},
+			{"key":"TEST-4","fields":{"customfield_10002":3}},
+			{"key":"TEST-5","fields":{"customfield_10002":0.5}},
+			{"key":"TEST-6","fields":{"customfield_10002":3}}]}`)
+	})
+
+	distribution, err := testClient.Board.GetEstimationDistribution(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if want := map[float64]int{0.5: 1, 3: 3, 5: 1}; !reflect.DeepEqual(distribution, want) {
+		t.Errorf("Expected distribution %v. Got %v", want, distribution)
+	}
+}
+
+func TestBoardService_GetAllBoards_ExtraParams(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board?includePrivate=true&name=Web&startAt=50")
+		fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+	})
+
+	opt := &BoardListOptions{
+		Name: "Web",
+		SearchOptions: SearchOptions{
+			StartAt:     50,
+			ExtraParams: url.Values{"includePrivate": {"true"}, "name": {"ignored"}},
+		},
+	}
+	if _, _, err := testClient.Board.GetAllBoards(opt); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestBoardService_GetStaleSprints(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint?state=active")
+		fmt.Fprint(w, `{"isLast":true,"values":[
+			{"id":1,"state":"active","name":"Overdue","endDate":"2017-04-10T15:22:00.000+02:00"},
+			{"id":2,"state":"active","name":"Running","endDate":"2017-04-25T15:22:00.000+02:00"},
+			{"id":3,"state":"active","name":"No end date"}]}`)
+	})
+
+	now := time.Date(2017, 4, 18, 12, 0, 0, 0, time.UTC)
+	sprints, err := testClient.Board.GetStaleSprints(1, now)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(sprints) != 1 || sprints[0].ID != 1 {
+		t.Errorf("Expected sprint 1 to be stale. Got %+v", sprints)
+	}
+}
+
+func TestBoardService_CountIssuesByAssignee(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/issue?fields=assignee&jql=statusCategory+%21%3D+Done")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":5,"issues":[
+			{"key":"TEST-1","fields":{"assignee":{"accountId":"5b10a2844c20165700ede21g","name":"fred"}}},
+			{"key":"TEST-2","fields":{"assignee":null}},
+			{"key":"TEST-3","fields":{"assignee":{"accountId":"5b10a2844c20165700ede21g","name":"fred"}}},
+			{"key":"TEST-4","fields":{"assignee":{"name":"charlie"}}},
+			{"key":"TEST-5","fields":{}}]}`)
+	})
+
+	counts, err := testClient.Board.CountIssuesByAssignee(1, "statusCategory != Done")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	want := map[string]int{"5b10a2844c20165700ede21g": 2, "charlie": 1, "": 2}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("Expected counts %v. Got %v", want, counts)
+	}
+}
+
+func TestBoardService_GetEpicsForBoard_Paginated(t *testing.T) {
+	setup()
+	defer teardown()
+	var startAts []string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/epic", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		startAts = append(startAts, r.URL.Query().Get("startAt"))
+		if r.URL.Query().Get("startAt") == "2" {
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"isLast":true,"values":[{"id":3,"key":"TEST-3"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":2,"isLast":false,"values":[{"id":1,"key":"TEST-1"},{"id":2,"key":"TEST-2"}]}`)
+	})
+
+	epics, _, err := testClient.Board.GetEpicsForBoard("1")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(epics) != 3 || epics[2].Key != "TEST-3" {
+		t.Errorf("Expected 3 epics. Got %+v", epics)
+	}
+	if want := []string{"", "2"}; !reflect.DeepEqual(startAts, want) {
+		t.Errorf("Expected pages at %v. Got %v", want, startAts)
+	}
+}
+
+func TestBoardService_GetEpicsForBoardWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/epic", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/epic?maxResults=10&startAt=20")
+		fmt.Fprint(w, `{"startAt":20,"maxResults":10,"isLast":true,"values":[{"id":21,"key":"TEST-21"}]}`)
+	})
+
+	epics, _, err := testClient.Board.GetEpicsForBoardWithOptions("1", &SearchOptions{StartAt: 20, MaxResults: 10})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(epics) != 1 || epics[0].Key != "TEST-21" {
+		t.Errorf("Unexpected epics: %+v", epics)
+	}
+}
+
+func TestBoardService_GetIssuesForBacklog_Paginated(t *testing.T) {
+	setup()
+	defer teardown()
+	var startAts []string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("maxResults"); got != "" {
+			t.Errorf("Expected the default page size of JIRA. Got maxResults %s", got)
+		}
+		startAts = append(startAts, r.URL.Query().Get("startAt"))
+		if r.URL.Query().Get("startAt") == "2" {
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"issues":[{"key":"TEST-3"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"issues":[{"key":"TEST-1"},{"key":"TEST-2"}]}`)
+	})
+
+	issues, _, err := testClient.Board.GetIssuesForBacklog("1")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 3 || issues[2].Key != "TEST-3" {
+		t.Errorf("Expected 3 issues. Got %+v", issues)
+	}
+	if want := []string{"", "2"}; !reflect.DeepEqual(startAts, want) {
+		t.Errorf("Expected pages at %v. Got %v", want, startAts)
+	}
+}
+
+func TestBoardService_GetIssuesForBacklogWithOptions_PagingInfo(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"issues":[{"key":"TEST-3"}]}`)
+	})
+
+	_, resp, err := testClient.Board.GetIssuesForBacklogWithOptions(1, &IssueListOptions{SearchOptions: SearchOptions{StartAt: 2, MaxResults: 2}})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.StartAt != 2 || resp.MaxResults != 2 || resp.Total != 3 {
+		t.Errorf("Unexpected paging info: startAt %d, maxResults %d, total %d", resp.StartAt, resp.MaxResults, resp.Total)
+	}
+}
+
+func TestBoardService_GetIssuesForBacklogWithOptions_JQL(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/backlog?fields=summary%2Cstatus&jql=type+%3D+Bug+AND+resolution+%3D+Unresolved")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TEST-1","fields":{"summary":"Crash"}}]}`)
+	})
+
+	opt := &IssueListOptions{JQL: "type = Bug AND resolution = Unresolved", Fields: []string{"summary", "status"}}
+	issues, _, err := testClient.Board.GetIssuesForBacklogWithOptions(1, opt)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 1 || issues[0].Fields.Summary != "Crash" {
+		t.Errorf("Unexpected issues: %+v", issues)
+	}
+}
+
+func TestBoardService_GetIssuesForEpicWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/epic/10000/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/epic/10000/issue?fields=summary&jql=resolution+%3D+Unresolved")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TEST-2","fields":{"summary":"Log in"}}]}`)
+	})
+
+	opt := &IssueListOptions{JQL: "resolution = Unresolved", Fields: []string{"summary"}}
+	issues, resp, err := testClient.Board.GetIssuesForEpicWithOptions("1", "10000", opt)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "TEST-2" {
+		t.Errorf("Unexpected issues: %+v", issues)
+	}
+	if resp.Total != 1 {
+		t.Errorf("Expected total 1. Got %d", resp.Total)
+	}
+}
+
+func TestBoardService_GetIssuesForBoard_OrderBy(t *testing.T) {
+	setup()
+	defer teardown()
+	var jqls []string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		jqls = append(jqls, r.URL.Query().Get("jql"))
+		if r.URL.Query().Get("startAt") == "1" {
+			fmt.Fprint(w, `{"startAt":1,"maxResults":1,"total":2,"issues":[{"key":"TEST-1"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":2,"issues":[{"key":"TEST-2"}]}`)
+	})
+
+	opt := &IssueListOptions{JQL: "status = Open", OrderBy: "ORDER BY updated DESC, key", OrderByRank: true}
+	issues, err := getAllIssues(opt, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return testClient.Board.GetIssuesForBoard(1, opt)
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("Expected 2 issues. Got %d", len(issues))
+	}
+	want := []string{"status = Open ORDER BY updated DESC, key", "status = Open ORDER BY updated DESC, key"}
+	if !reflect.DeepEqual(jqls, want) {
+		t.Errorf("Expected JQL %q. Got %q", want, jqls)
+	}
+	if opt.JQL != "status = Open" {
+		t.Errorf("Expected the options not to be modified. Got JQL %q", opt.JQL)
+	}
+}
+
+func TestBoardService_GetIssuesForBoard_InvalidOrderBy(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request with JQL %q", r.URL.Query().Get("jql"))
+	})
+
+	for _, opt := range []*IssueListOptions{
+		{OrderBy: "updated DESC; DROP"},
+		{OrderBy: "updated DESC) OR (project = SECRET"},
+		{OrderBy: "cf[10002] ASC, priority SIDEWAYS"},
+		{JQL: "project = TEST ORDER BY created", OrderBy: "updated"},
+	} {
+		if _, _, err := testClient.Board.GetIssuesForBoard(1, opt); err == nil {
+			t.Errorf("Expected an error for JQL %q and OrderBy %q. Got none", opt.JQL, opt.OrderBy)
+		}
+	}
+}
+
+func TestBoardService_GetProjects(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/project", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("startAt") == "2" {
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"isLast":true,"values":[{"id":"10002","key":"OPS","name":"Operations"}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/project")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"isLast":false,"values":[
+			{"id":"10000","key":"WEB","name":"Website"},{"id":"10001","key":"APP","name":"App"}]}`)
+	})
+
+	projects, err := testClient.Board.GetProjects(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	var keys []string
+	for _, project := range projects {
+		keys = append(keys, project.Key)
+	}
+	if want := []string{"WEB", "APP", "OPS"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected projects %v. Got %v", want, keys)
+	}
+	if projects[2].ID != "10002" || projects[2].Name != "Operations" {
+		t.Errorf("Unexpected project: %+v", projects[2])
+	}
+}
+
+func TestBoardService_GetBoardOverview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	raw, err := ioutil.ReadFile("./mocks/sprints.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc("/rest/agile/1.0/board/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"Team A","type":"scrum"}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"Team A","filter":{"id":"10000"}}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, string(raw))
+	})
+
+	overview, err := testClient.Board.GetBoardOverview(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if overview.Board == nil || overview.Board.Name != "Team A" {
+		t.Errorf("Unexpected board: %+v", overview.Board)
+	}
+	if overview.Configuration == nil || overview.Configuration.Filter.ID != "10000" {
+		t.Errorf("Unexpected configuration: %+v", overview.Configuration)
+	}
+	if len(overview.Sprints) != 4 {
+		t.Errorf("Expected 4 sprints. Got %d", len(overview.Sprints))
+	}
+}
+
+func TestBoardService_GetBoardOverview_PartialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"name":"Team A","type":"scrum"}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":1,"name":"Sprint 1","state":"active"}]}`)
+	})
+
+	overview, err := testClient.Board.GetBoardOverview(1)
+	if err == nil || !strings.Contains(err.Error(), "configuration") {
+		t.Errorf("Expected an error for the configuration. Got %v", err)
+	}
+	if overview == nil || overview.Board == nil || len(overview.Sprints) != 1 {
+		t.Fatalf("Expected the board and its sprints. Got %+v", overview)
+	}
+	if overview.ConfigurationErr == nil || overview.BoardErr != nil || overview.SprintsErr != nil {
+		t.Errorf("Expected only the configuration to fail. Got %+v", overview)
+	}
+}
+
+func TestBoardService_CanAdministerBoards(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/myself?expand=groups")
+		fmt.Fprint(w, `{"name":"fred","groups":{"size":2,"items":[{"name":"jira-users"},{"name":"agile-admins"}]}}`)
+	})
+
+	can, err := testClient.Board.CanAdministerBoards()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if can {
+		t.Error("Expected fred not to be a member of the default admin groups")
+	}
+
+	testClient.BoardAdminGroups = []string{"agile-admins"}
+	can, err = testClient.Board.CanAdministerBoards()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !can {
+		t.Error("Expected fred to be a member of agile-admins")
+	}
+}
+
+func TestBoardService_ResolveEstimationFieldName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		calls++
+		fmt.Fprint(w, `[{"id":"summary","name":"Summary"},{"id":"customfield_10016","name":"Story Points","custom":true}]`)
+	})
+
+	cfg := &BoardConfiguration{
+		ID:         1,
+		Estimation: Estimation{Type: "field", Field: BoardEstimationField{FieldId: "customfield_10016"}},
+	}
+	for i := 0; i < 2; i++ {
+		name, err := testClient.Board.ResolveEstimationFieldName(cfg)
+		if err != nil {
+			t.Fatalf("Error given: %s", err)
+		}
+		if name != "Story Points" {
+			t.Errorf("Expected name Story Points. Got %s", name)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the field list to be fetched once. Got %d calls", calls)
+	}
+
+	testClient.InvalidateFieldCache()
+	if _, err := testClient.Board.ResolveEstimationFieldName(cfg); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the field list to be fetched again after InvalidateFieldCache. Got %d calls", calls)
+	}
+
+	cfg.Estimation.Field.FieldId = "customfield_99999"
+	if _, err := testClient.Board.ResolveEstimationFieldName(cfg); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	cfg.Estimation = Estimation{Type: "none"}
+	if _, err := testClient.Board.ResolveEstimationFieldName(cfg); err == nil {
+		t.Error("Expected an error for a board without estimation")
+	}
+}
+
+func TestBoardService_GetBoardByName(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("startAt") == "" {
+			testRequestURL(t, r, "/rest/agile/1.0/board?name=web")
+			fmt.Fprint(w, `{"startAt":0,"isLast":false,"values":[{"id":1,"name":"Web Team"}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/board?name=web&startAt=1")
+		fmt.Fprint(w, `{"startAt":1,"isLast":true,"values":[{"id":2,"name":"Web"}]}`)
+	})
+
+	board, err := testClient.Board.GetBoardByName("web")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if board.ID != 2 {
+		t.Errorf("Expected board 2. Got %d", board.ID)
+	}
+}
+
+func testGetBoardByNameHandler(t *testing.T, name, values string) {
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("name"); got != name {
+			t.Errorf("Expected name filter %q. Got %q", name, got)
+		}
+		fmt.Fprintf(w, `{"isLast":true,"values":%s}`, values)
+	})
+}
+
+func TestBoardService_GetBoardByName_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+	testGetBoardByNameHandler(t, "Web", `[{"id":1,"name":"Web Team"},{"id":2,"name":"Webhooks Board"}]`)
+
+	board, err := testClient.Board.GetBoardByName("Web")
+	if err == nil {
+		t.Fatalf("Expected an error. Got board %+v", board)
+	}
+	if !strings.Contains(err.Error(), "No board named") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestBoardService_GetBoardByName_Ambiguous(t *testing.T) {
+	setup()
+	defer teardown()
+	testGetBoardByNameHandler(t, "Web", `[{"id":1,"name":"web"},{"id":2,"name":"Web Team"},{"id":3,"name":"WEB"}]`)
+
+	board, err := testClient.Board.GetBoardByName("Web")
+	if err == nil {
+		t.Fatalf("Expected an error. Got board %+v", board)
+	}
+	if !strings.Contains(err.Error(), "ambiguous") || !strings.Contains(err.Error(), "1, 3") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestBoardService_GetBoardByName_AmbiguousCase(t *testing.T) {
+	setup()
+	defer teardown()
+	testGetBoardByNameHandler(t, "Web", `[{"id":1,"name":"Web"},{"id":2,"name":"Web Team"},{"id":3,"name":"Web"}]`)
+
+	board, err := testClient.Board.GetBoardByName("Web")
+	if err == nil {
+		t.Fatalf("Expected an error. Got board %+v", board)
+	}
+	if !strings.Contains(err.Error(), "ambiguous") || !strings.Contains(err.Error(), "1, 3") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestBoardService_GetBoardByName_PrefersCase(t *testing.T) {
+	setup()
+	defer teardown()
+	testGetBoardByNameHandler(t, "Web", `[{"id":1,"name":"web"},{"id":2,"name":"Web Team"},{"id":3,"name":"Web"}]`)
+
+	board, err := testClient.Board.GetBoardByName("Web")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if board.ID != 3 {
+		t.Errorf("Expected board 3. Got %d", board.ID)
+	}
+}
+
+func TestBoardService_GetBoardIssuesWithExtraJQL_NextPageToken(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch token := r.URL.Query().Get("nextPageToken"); token {
+		case "":
+			if r.URL.Query().Get("startAt") != "" {
+				t.Errorf("Expected no startAt. Got %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"issues":[{"key":"TEST-1"},{"key":"TEST-2"}],"nextPageToken":"abc"}`)
+		case "abc":
+			fmt.Fprint(w, `{"issues":[{"key":"TEST-3"}]}`)
+		default:
+			t.Errorf("Unexpected nextPageToken %s", token)
+		}
+	})
+
+	issues, err := testClient.Board.GetBoardIssuesWithExtraJQL(1, "labels = urgent", nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 3 || issues[2].Key != "TEST-3" {
+		t.Errorf("Expected issues TEST-1 to TEST-3. Got %+v", issues)
+	}
+}
+
+func TestBoardService_GroupBoardsByProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board?boardType=scrum")
+		fmt.Fprint(w, `{"startAt":0,"isLast":true,"values":[
+			{"id":1,"name":"Web","type":"scrum"},
+			{"id":2,"name":"Platform","type":"scrum"},
+			{"id":3,"name":"Empty","type":"scrum"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/project", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":"10000","key":"WEB"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/2/project", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":"10000","key":"WEB"},{"id":"10001","key":"API"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/3/project", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+	})
+
+	groups, err := testClient.Board.GroupBoardsByProject(&BoardListOptions{BoardType: "scrum"}, 2)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	ids := map[string][]int{}
+	for key, boards := range groups {
+		for _, board := range boards {
+			ids[key] = append(ids[key], board.ID)
+		}
+	}
+	if want := map[string][]int{"WEB": {1, 2}, "API": {2}}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected boards %v. Got %v", want, ids)
+	}
+}
+
+func TestBoardService_CheckBoardHealth_InvalidJQL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"filter":{"id":"10000"}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/filter/10000", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"10000","jql":"project = TEST AND cf[10999] = x"}`)
+	})
+	testMux.HandleFunc("/rest/api/2/jql/parse", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"queries":[{"query":"project = TEST AND cf[10999] = x","errors":["Field 'cf[10999]' does not exist or you do not have permission to view it."]}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no issues to be counted for invalid JQL")
+	})
+
+	health, err := testClient.Board.CheckBoardHealth(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if health.Healthy() || len(health.JQLErrors) != 1 || health.JQL != "project = TEST AND cf[10999] = x" {
+		t.Errorf("Expected an unhealthy board with 1 JQL error. Got %+v", health)
+	}
+}
+
+func TestBoardService_CheckBoardHealth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"filter":{"id":"10000"}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/filter/10000", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"10000","jql":"project = TEST"}`)
+	})
+	testMux.HandleFunc("/rest/api/2/jql/parse", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"queries":[{"query":"project = TEST","errors":[]}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/issue?fields=key&maxResults=1")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":42,"issues":[{"key":"TEST-1"}]}`)
+	})
+
+	health, err := testClient.Board.CheckBoardHealth(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !health.Healthy() || health.Total != 42 {
+		t.Errorf("Expected a healthy board with 42 issues. Got %+v", health)
+	}
+}
+
+func TestBoardService_GetQuickFilters(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/quickfilter", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("startAt") == "" {
+			testRequestURL(t, r, "/rest/agile/1.0/board/1/quickfilter")
+			fmt.Fprint(w, `{"maxResults":1,"startAt":0,"total":2,"isLast":false,"values":[
+				{"id":1,"boardId":1,"name":"Bugs","jql":"issuetype = Bug","description":"Only bugs","position":0}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/quickfilter?startAt=1")
+		fmt.Fprint(w, `{"maxResults":1,"startAt":1,"total":2,"isLast":true,"values":[
+			{"id":2,"boardId":1,"name":"Mine","jql":"assignee = currentUser()","position":1}]}`)
+	})
+
+	filters, err := testClient.Board.GetQuickFilters(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(filters) != 2 {
+		t.Fatalf("Expected 2 quick filters. Got %d", len(filters))
+	}
+	if want := (QuickFilter{ID: 1, BoardID: 1, Name: "Bugs", JQL: "issuetype = Bug", Description: "Only bugs"}); filters[0] != want {
+		t.Errorf("Expected quick filter %+v. Got %+v", want, filters[0])
+	}
+	if filters[1].ID != 2 || filters[1].JQL != "assignee = currentUser()" {
+		t.Errorf("Unexpected quick filter: %+v", filters[1])
+	}
+}
+
+func TestBoardService_GetQuickFilter(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/quickfilter/2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/quickfilter/2")
+		fmt.Fprint(w, `{"id":2,"boardId":1,"name":"Mine","jql":"assignee = currentUser()","position":1}`)
+	})
+
+	filter, _, err := testClient.Board.GetQuickFilter(1, 2)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if filter.ID != 2 || filter.Name != "Mine" || filter.Position != 1 {
+		t.Errorf("Unexpected quick filter: %+v", filter)
+	}
+}
+
+func TestBoardService_GetBoardIssuesWithExtraJQL_RepeatedNextPageToken(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := 0
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 3 {
+			t.Fatalf("Expected paging to stop. Got %d requests", requests)
+		}
+		if r.URL.Query().Get("nextPageToken") == "" {
+			fmt.Fprint(w, `{"issues":[{"key":"TEST-1"}],"nextPageToken":"abc"}`)
+			return
+		}
+		// A misbehaving server returns the same token again
+		fmt.Fprint(w, `{"issues":[{"key":"TEST-2"}],"nextPageToken":"abc"}`)
+	})
+
+	issues, err := testClient.Board.GetBoardIssuesWithExtraJQL(1, "labels = urgent", nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 2 || requests != 2 {
+		t.Errorf("Expected 2 issues in 2 requests. Got %d issues in %d requests", len(issues), requests)
+	}
+}
+
+func TestBoardService_GetBoardIssuesWithExtraJQL_EmptyTokenPage(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := 0
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 3 {
+			t.Fatalf("Expected paging to stop. Got %d requests", requests)
+		}
+		fmt.Fprintf(w, `{"issues":[],"nextPageToken":"token-%d"}`, requests)
+	})
+
+	issues, err := testClient.Board.GetBoardIssuesWithExtraJQL(1, "labels = urgent", nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 0 || requests != 1 {
+		t.Errorf("Expected no issues in 1 request. Got %d issues in %d requests", len(issues), requests)
+	}
+}
+
+func TestBoardService_GetEpicsForBoard_EmptyPage(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := 0
+	testMux.HandleFunc("/rest/agile/1.0/board/1/epic", func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 2 {
+			t.Fatalf("Expected paging to stop. Got %d requests", requests)
+		}
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"startAt":0,"maxResults":1,"isLast":false,"values":[{"id":100,"key":"AR-1"}]}`)
+			return
+		}
+		// isLast is missing, the empty page ends the paging
+		fmt.Fprint(w, `{"startAt":1,"maxResults":1,"values":[]}`)
+	})
+
+	epics, _, err := testClient.Board.GetEpicsForBoard("1")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(epics) != 1 || requests != 2 {
+		t.Errorf("Expected 1 epic in 2 requests. Got %d epics in %d requests", len(epics), requests)
+	}
+}
+
+func TestBoardService_GetIssuesForBacklog_EmptyPage(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := 0
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 2 {
+			t.Fatalf("Expected paging to stop. Got %d requests", requests)
+		}
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":5,"issues":[{"key":"AR-1"}]}`)
+			return
+		}
+		// The total is out of date, the empty page ends the paging
+		fmt.Fprint(w, `{"startAt":1,"maxResults":1,"total":5,"issues":[]}`)
+	})
+
+	issues, _, err := testClient.Board.GetIssuesForBacklog("1")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 1 || requests != 2 {
+		t.Errorf("Expected 1 issue in 2 requests. Got %d issues in %d requests", len(issues), requests)
+	}
+}
+
+func TestBoardService_GetAllSprints_Paging(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("startAt") == "" {
+			testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint")
+			fmt.Fprint(w, `{"maxResults":2,"startAt":0,"isLast":false,"values":[{"id":1},{"id":2}]}`)
+			return
+		}
+		testRequestURL(t, r, "/rest/agile/1.0/board/1/sprint?startAt=2")
+		fmt.Fprint(w, `{"maxResults":2,"startAt":2,"isLast":true,"values":[{"id":3}]}`)
+	})
+
+	sprints, _, err := testClient.Board.GetAllSprints("1")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(sprints) != 3 || sprints[2].ID != 3 {
+		t.Errorf("Expected sprints 1 to 3. Got %+v", sprints)
+	}
+}
+
+func TestBoardService_FindDuplicateBoards_NoFilterID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":1,"name":"Team A"},{"id":2,"name":"Team B"}]}`)
+	})
+	for _, boardID := range []int{1, 2} {
+		boardID := boardID
+		testMux.HandleFunc(fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID), func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprintf(w, `{"id":%d,"filter":{}}`, boardID)
+		})
+	}
+
+	duplicates, err := testClient.Board.FindDuplicateBoards()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(duplicates) != 0 {
+		t.Errorf("Expected no duplicates. Got %+v", duplicates)
+	}
+}
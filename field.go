@@ -0,0 +1,47 @@
+package jira
+
+// FieldService handles fields for the JIRA instance / API.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/field
+type FieldService struct {
+	client *Client
+}
+
+// Field represents a system or custom field of JIRA
+type Field struct {
+	ID          string      `json:"id,omitempty" structs:"id,omitempty"`
+	Key         string      `json:"key,omitempty" structs:"key,omitempty"`
+	Name        string      `json:"name,omitempty" structs:"name,omitempty"`
+	Custom      bool        `json:"custom,omitempty" structs:"custom,omitempty"`
+	Navigable   bool        `json:"navigable,omitempty" structs:"navigable,omitempty"`
+	Searchable  bool        `json:"searchable,omitempty" structs:"searchable,omitempty"`
+	ClauseNames []string    `json:"clauseNames,omitempty" structs:"clauseNames,omitempty"`
+	Schema      FieldSchema `json:"schema,omitempty" structs:"schema,omitempty"`
+}
+
+// FieldSchema describes the type of the values of a field
+type FieldSchema struct {
+	Type     string `json:"type,omitempty" structs:"type,omitempty"`
+	Items    string `json:"items,omitempty" structs:"items,omitempty"`
+	System   string `json:"system,omitempty" structs:"system,omitempty"`
+	Custom   string `json:"custom,omitempty" structs:"custom,omitempty"`
+	CustomID int    `json:"customId,omitempty" structs:"customId,omitempty"`
+}
+
+// GetList returns all system and custom fields of the JIRA instance.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/field-getFields
+func (s *FieldService) GetList() ([]Field, *Response, error) {
+	apiEndpoint := "rest/api/2/field"
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := []Field{}
+	resp, err := s.client.Do(req, &fields)
+	if err != nil {
+		return nil, resp, err
+	}
+	return fields, resp, nil
+}
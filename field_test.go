@@ -0,0 +1,34 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFieldService_GetList(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/field"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `[{"id":"summary","key":"summary","name":"Summary","custom":false,"navigable":true,"searchable":true,
+			"clauseNames":["summary"],"schema":{"type":"string","system":"summary"}},
+			{"id":"customfield_10021","key":"customfield_10021","name":"Flagged","custom":true,"navigable":true,"searchable":true,
+			"clauseNames":["cf[10021]","Flagged"],"schema":{"type":"array","items":"option",
+			"custom":"com.atlassian.jira.plugin.system.customfieldtypes:multicheckboxes","customId":10021}}]`)
+	})
+
+	fields, _, err := testClient.Field.GetList()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 fields. Got %d", len(fields))
+	}
+	if flagged := fields[1]; !flagged.Custom || flagged.Schema.CustomID != 10021 || flagged.Name != "Flagged" {
+		t.Errorf("Unexpected custom field: %+v", flagged)
+	}
+}
@@ -0,0 +1,153 @@
+package jira
+
+import (
+	"fmt"
+)
+
+// FilterService handles filters for the JIRA instance / API.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/filter
+type FilterService struct {
+	client *Client
+}
+
+// Filter represents a saved JQL filter of JIRA
+type Filter struct {
+	ID               string            `json:"id,omitempty" structs:"id,omitempty"`
+	Self             string            `json:"self,omitempty" structs:"self,omitempty"`
+	Name             string            `json:"name,omitempty" structs:"name,omitempty"`
+	Description      string            `json:"description,omitempty" structs:"description,omitempty"`
+	Owner            *User             `json:"owner,omitempty" structs:"owner,omitempty"`
+	JQL              string            `json:"jql,omitempty" structs:"jql,omitempty"`
+	ViewURL          string            `json:"viewUrl,omitempty" structs:"viewUrl,omitempty"`
+	SearchURL        string            `json:"searchUrl,omitempty" structs:"searchUrl,omitempty"`
+	Favourite        bool              `json:"favourite,omitempty" structs:"favourite,omitempty"`
+	SharePermissions []SharePermission `json:"sharePermissions,omitempty" structs:"sharePermissions,omitempty"`
+}
+
+// SharePermission represents who a filter is shared with.
+// Type is one of "global", "loggedin", "group", "project" and "projectRole".
+type SharePermission struct {
+	ID    int        `json:"id,omitempty" structs:"id,omitempty"`
+	Type  string     `json:"type,omitempty" structs:"type,omitempty"`
+	Group *UserGroup `json:"group,omitempty" structs:"group,omitempty"`
+}
+
+// sharePermissionPayload is the request payload to add a share permission to a filter
+type sharePermissionPayload struct {
+	Type      string `json:"type"`
+	GroupName string `json:"groupname,omitempty"`
+}
+
+// Create creates a filter in JIRA. The filter is owned by the current user and not shared.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/filter-createFilter
+func (s *FilterService) Create(filter *Filter) (*Filter, *Response, error) {
+	apiEndpoint := "rest/api/2/filter"
+	if filter.JQL != "" {
+		f := *filter
+		f.JQL = s.client.normalizeJQL(f.JQL)
+		filter = &f
+	}
+	req, err := s.client.NewRequest("POST", apiEndpoint, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(Filter)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result, resp, nil
+}
+
+// Get returns a filter, for a given filter Id.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/filter-getFilter
+func (s *FilterService) Get(filterID string) (*Filter, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/filter/%s", filterID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := new(Filter)
+	resp, err := s.client.Do(req, filter)
+	if err != nil {
+		return nil, resp, err
+	}
+	return filter, resp, nil
+}
+
+// jqlParseRequest is the request payload to parse JQL queries
+type jqlParseRequest struct {
+	Queries []string `json:"queries"`
+}
+
+// jqlParseResult is only a small wrapper around the ValidateJQL method
+// to be able to parse the results
+type jqlParseResult struct {
+	Queries []struct {
+		Query  string   `json:"query"`
+		Errors []string `json:"errors"`
+	} `json:"queries"`
+}
+
+// ValidateJQL checks jql strictly, e.g. for references to fields or versions that do not exist (anymore).
+// The problems found by JIRA are returned, none if jql is valid.
+//
+// JIRA API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/#api-rest-api-2-jql-parse-post
+func (s *FilterService) ValidateJQL(jql string) ([]string, *Response, error) {
+	apiEndpoint := "rest/api/2/jql/parse?validation=strict"
+	req, err := s.client.NewRequest("POST", apiEndpoint, jqlParseRequest{Queries: []string{s.client.normalizeJQL(jql)}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(jqlParseResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(result.Queries) != 1 {
+		return nil, resp, fmt.Errorf("Expected 1 parsed query. Got %d", len(result.Queries))
+	}
+	return result.Queries[0].Errors, resp, nil
+}
+
+// shareWithGroup adds a share permission for the group groupName to a filter, for a given filter Id.
+// All share permissions of the filter are returned.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/filter-addSharePermission
+func (s *FilterService) shareWithGroup(filterID, groupName string) ([]SharePermission, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/filter/%s/permission", filterID)
+	req, err := s.client.NewRequest("POST", apiEndpoint, sharePermissionPayload{Type: "group", GroupName: groupName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissions := []SharePermission{}
+	resp, err := s.client.Do(req, &permissions)
+	if err != nil {
+		return nil, resp, err
+	}
+	return permissions, resp, nil
+}
+
+// CreateSharedFilter creates a filter for jql and shares it with the group groupName, e.g. to base a board on it.
+// Use the Id of the returned filter as Board.FilterID for BoardService.CreateBoard.
+// If the filter can not be shared, the error is returned together with the created filter.
+func (s *FilterService) CreateSharedFilter(name, jql, groupName string) (*Filter, error) {
+	filter, _, err := s.Create(&Filter{Name: name, JQL: jql})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, _, err := s.shareWithGroup(filter.ID, groupName)
+	if err != nil {
+		return filter, fmt.Errorf("Could not share filter %s with group %s: %s", filter.ID, groupName, err)
+	}
+	filter.SharePermissions = permissions
+	return filter, nil
+}
@@ -0,0 +1,92 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFilterService_CreateSharedFilter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/filter", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/rest/api/2/filter")
+
+		var filter Filter
+		json.NewDecoder(r.Body).Decode(&filter)
+		if filter.Name != "Team A" || filter.JQL != "project = TEST" {
+			t.Errorf("Unexpected filter payload: %+v", filter)
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id":"10000","self":"https://jira.example.com/rest/api/2/filter/10000","name":"Team A","jql":"project = TEST"}`)
+	})
+	testMux.HandleFunc("/rest/api/2/filter/10000/permission", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["type"] != "group" || payload["groupname"] != "team-a" {
+			t.Errorf("Unexpected share permission payload: %v", payload)
+		}
+		fmt.Fprint(w, `[{"id":10010,"type":"group","group":{"name":"team-a","self":"https://jira.example.com/rest/api/2/group?groupname=team-a"}}]`)
+	})
+
+	filter, err := testClient.Filter.CreateSharedFilter("Team A", "project = TEST", "team-a")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if filter == nil || filter.ID != "10000" {
+		t.Fatalf("Expected filter 10000. Got %+v", filter)
+	}
+	if len(filter.SharePermissions) != 1 || filter.SharePermissions[0].Group == nil || filter.SharePermissions[0].Group.Name != "team-a" {
+		t.Errorf("Expected filter to be shared with team-a. Got %+v", filter.SharePermissions)
+	}
+}
+
+func TestFilterService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/filter/10000"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"id":"10000","name":"All open bugs","jql":"type = Bug and resolution is empty","favourite":true}`)
+	})
+
+	filter, _, err := testClient.Filter.Get("10000")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if filter == nil || filter.JQL != "type = Bug and resolution is empty" || !filter.Favourite {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+}
+
+func TestFilterService_ValidateJQL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/jql/parse", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/rest/api/2/jql/parse?validation=strict")
+
+		var payload jqlParseRequest
+		json.NewDecoder(r.Body).Decode(&payload)
+		if len(payload.Queries) != 1 || payload.Queries[0] != "fixVersion = 1.0" {
+			t.Errorf("Unexpected payload: %+v", payload)
+		}
+		fmt.Fprint(w, `{"queries":[{"query":"fixVersion = 1.0","errors":["The value '1.0' does not exist for the field 'fixVersion'."]}]}`)
+	})
+
+	errs, _, err := testClient.Filter.ValidateJQL("fixVersion = 1.0")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(errs) != 1 || errs[0] != "The value '1.0' does not exist for the field 'fixVersion'." {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
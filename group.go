@@ -2,6 +2,7 @@ package jira
 
 import (
 	"fmt"
+	"net/url"
 )
 
 // GroupService handles Groups for the JIRA instance / API.
@@ -20,6 +21,32 @@ type groupMembersResult struct {
 	Members    []GroupMember `json:"values"`
 }
 
+// groupUsersResult is only a small wrapper around the GetGroupMembers method
+// to be able to parse the results
+type groupUsersResult struct {
+	StartAt    int    `json:"startAt"`
+	MaxResults int    `json:"maxResults"`
+	Total      int    `json:"total"`
+	IsLast     bool   `json:"isLast"`
+	Users      []User `json:"values"`
+}
+
+// Group represents a JIRA group, as returned by GroupService.Add
+type Group struct {
+	Name  string       `json:"name,omitempty" structs:"name,omitempty"`
+	Self  string       `json:"self,omitempty" structs:"self,omitempty"`
+	Users GroupMembers `json:"users,omitempty" structs:"users,omitempty"`
+}
+
+// GroupMembers is the paginated list of the members of a Group
+type GroupMembers struct {
+	Size       int           `json:"size,omitempty" structs:"size,omitempty"`
+	Items      []GroupMember `json:"items,omitempty" structs:"items,omitempty"`
+	MaxResults int           `json:"max-results,omitempty" structs:"max-results,omitempty"`
+	StartIndex int           `json:"start-index,omitempty" structs:"start-index,omitempty"`
+	EndIndex   int           `json:"end-index,omitempty" structs:"end-index,omitempty"`
+}
+
 // GroupMember reflects a single member of a group
 type GroupMember struct {
 	Self         string `json:"self,omitempty"`
@@ -51,3 +78,108 @@ func (s *GroupService) Get(name string) ([]GroupMember, *Response, error) {
 
 	return group.Members, resp, nil
 }
+
+// GetGroupMembers returns all members of the specified group and its subgroups, including inactive users.
+// The members are fetched page by page, options control the first page and the page size.
+// Use User.Active to tell active from inactive members.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/server/#api/2/group-getUsersFromGroup
+func (s *GroupService) GetGroupMembers(groupName string, options *SearchOptions) ([]User, error) {
+	opt := struct {
+		GroupName            string `url:"groupname"`
+		IncludeInactiveUsers bool   `url:"includeInactiveUsers"`
+		SearchOptions
+	}{GroupName: groupName, IncludeInactiveUsers: true}
+	if options != nil {
+		opt.SearchOptions = *options
+	}
+
+	users := []User{}
+	for {
+		apiEndpoint, err := addOptions("rest/api/2/group/member", opt)
+		if err != nil {
+			return nil, err
+		}
+		req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result := new(groupUsersResult)
+		if _, err := s.client.Do(req, result); err != nil {
+			return nil, err
+		}
+		users = append(users, result.Users...)
+
+		if result.IsLast || len(result.Users) == 0 {
+			return users, nil
+		}
+		opt.StartAt = result.StartAt + len(result.Users)
+	}
+}
+
+// AddUser adds the user with the given account Id to a group.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/group-addUserToGroup
+func (s *GroupService) AddUser(groupName, accountID string) (*Response, error) {
+	apiEndpoint := "rest/api/2/group/user?groupname=" + url.QueryEscape(groupName)
+	payload := struct {
+		AccountID string `json:"accountId"`
+	}{accountID}
+	req, err := s.client.NewRequest("POST", apiEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
+
+// RemoveUser removes the user with the given account Id from a group.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/group-removeUserFromGroup
+func (s *GroupService) RemoveUser(groupName, accountID string) (*Response, error) {
+	params := url.Values{}
+	params.Set("groupname", groupName)
+	params.Set("accountId", accountID)
+	req, err := s.client.NewRequest("DELETE", "rest/api/2/group/user?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
+
+// Add adds the user with the given username to a group and returns the group.
+// Use AddUser on JIRA Cloud, where users are identified by account Id.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/server/#api/2/group-addUserToGroup
+func (s *GroupService) Add(groupName, username string) (*Group, *Response, error) {
+	apiEndpoint := "rest/api/2/group/user?groupname=" + url.QueryEscape(groupName)
+	payload := struct {
+		Name string `json:"name"`
+	}{username}
+	req, err := s.client.NewRequest("POST", apiEndpoint, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group := new(Group)
+	resp, err := s.client.Do(req, group)
+	if err != nil {
+		return nil, resp, err
+	}
+	return group, resp, nil
+}
+
+// Remove removes the user with the given username from a group.
+// Use RemoveUser on JIRA Cloud, where users are identified by account Id.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/server/#api/2/group-removeUserFromGroup
+func (s *GroupService) Remove(groupName, username string) (*Response, error) {
+	params := url.Values{}
+	params.Set("groupname", groupName)
+	params.Set("username", username)
+	req, err := s.client.NewRequest("DELETE", "rest/api/2/group/user?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
@@ -0,0 +1,129 @@
+package jira
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGroupService_GetGroupMembers(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/group/member"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("startAt") {
+		case "":
+			testRequestURL(t, r, testAPIEndpoint+"?groupname=jira-users&includeInactiveUsers=true&maxResults=2")
+			fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"isLast":false,"values":[
+				{"name":"alice","key":"alice","displayName":"Alice","active":true},
+				{"name":"bob","key":"bob","displayName":"Bob","active":false}]}`)
+		case "2":
+			testRequestURL(t, r, testAPIEndpoint+"?groupname=jira-users&includeInactiveUsers=true&maxResults=2&startAt=2")
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"isLast":true,"values":[
+				{"name":"carol","key":"carol","displayName":"Carol","active":true}]}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+	})
+
+	users, err := testClient.Group.GetGroupMembers("jira-users", &SearchOptions{MaxResults: 2})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("Expected 3 users. Got %d", len(users))
+	}
+	if users[1].Name != "bob" || users[1].Active {
+		t.Errorf("Expected inactive user bob. Got %+v", users[1])
+	}
+	if users[2].Name != "carol" {
+		t.Errorf("Expected user carol. Got %+v", users[2])
+	}
+}
+
+func TestGroupService_AddUser(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/group/user"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint+"?groupname=jira-users")
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"accountId":"5b10a2844c20165700ede21g"}`+"\n"; got != want {
+			t.Errorf("Expected body %q. Got %q", want, got)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"name":"jira-users"}`)
+	})
+
+	if _, err := testClient.Group.AddUser("jira-users", "5b10a2844c20165700ede21g"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestGroupService_RemoveUser(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/group/user"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, testAPIEndpoint+"?accountId=5b10a2844c20165700ede21g&groupname=jira-users")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if _, err := testClient.Group.RemoveUser("jira-users", "5b10a2844c20165700ede21g"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestGroupService_Add(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/group/user"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint+"?groupname=jira+developers")
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"name":"charlie"}`+"\n"; got != want {
+			t.Errorf("Expected body %q. Got %q", want, got)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"name":"jira developers","self":"https://jira.example.com/rest/api/2/group?groupname=jira+developers",
+			"users":{"size":2,"items":[{"name":"admin"},{"name":"charlie"}],"max-results":50,"start-index":0,"end-index":1}}`)
+	})
+
+	group, _, err := testClient.Group.Add("jira developers", "charlie")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if group.Name != "jira developers" || group.Users.Size != 2 || len(group.Users.Items) != 2 {
+		t.Errorf("Unexpected group: %+v", group)
+	}
+	if group.Users.Items[1].Name != "charlie" || group.Users.EndIndex != 1 {
+		t.Errorf("Unexpected group members: %+v", group.Users)
+	}
+}
+
+func TestGroupService_Remove(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/group/user"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, testAPIEndpoint+"?groupname=jira+developers&username=charlie")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if _, err := testClient.Group.Remove("jira developers", "charlie"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
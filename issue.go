@@ -2,6 +2,7 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,8 +10,10 @@ import (
 	"mime/multipart"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/fatih/structs"
 	"github.com/google/go-querystring/query"
@@ -37,6 +40,9 @@ type Issue struct {
 	Key       string       `json:"key,omitempty" structs:"key,omitempty"`
 	Fields    *IssueFields `json:"fields,omitempty" structs:"fields,omitempty"`
 	Changelog *Changelog   `json:"changelog,omitempty" structs:"changelog,omitempty"`
+	// Properties contains the entity properties of the issue, keyed by property key.
+	// They are only returned if requested, see IssueListOptions.Properties.
+	Properties map[string]interface{} `json:"properties,omitempty" structs:"properties,omitempty"`
 }
 
 // ChangelogItems reflects one single changelog item of a history item
@@ -294,6 +300,7 @@ type transitionResult struct {
 type Transition struct {
 	ID     string                     `json:"id" structs:"id"`
 	Name   string                     `json:"name" structs:"name"`
+	To     Status                     `json:"to" structs:"to"`
 	Fields map[string]TransitionField `json:"fields" structs:"fields"`
 }
 
@@ -304,7 +311,9 @@ type TransitionField struct {
 
 // CreateTransitionPayload is used for creating new issue transitions
 type CreateTransitionPayload struct {
-	Transition TransitionPayload `json:"transition" structs:"transition"`
+	Transition TransitionPayload       `json:"transition" structs:"transition"`
+	Fields     map[string]interface{} `json:"fields,omitempty" structs:"fields,omitempty"`
+	Update     map[string]interface{} `json:"update,omitempty" structs:"update,omitempty"`
 }
 
 // TransitionPayload represents the request payload of Transistion calls like DoTransition
@@ -436,15 +445,27 @@ type SearchOptions struct {
 	MaxResults int `url:"maxResults,omitempty"`
 	// Expand: Expand specific sections in the returned issues
 	Expand string `url:"expand,omitempty"`
+	// NextPageToken requests the page following a previous response of an endpoint with cursor based paging,
+	// see Response.NextPageToken. Such endpoints ignore StartAt.
+	NextPageToken string `url:"nextPageToken,omitempty"`
+	// ExtraParams are added to the query string of the request, e.g. for parameters without a typed option.
+	// Typed options take precedence over extra parameters with the same name.
+	ExtraParams url.Values `url:"-"`
+}
+
+// extraParams returns the ExtraParams of the options. It is promoted to all options embedding SearchOptions.
+func (o SearchOptions) extraParams() url.Values {
+	return o.ExtraParams
 }
 
 // searchResult is only a small wrapper around the Search (with JQL) method
 // to be able to parse the results
 type searchResult struct {
-	Issues     []Issue `json:"issues" structs:"issues"`
-	StartAt    int     `json:"startAt" structs:"startAt"`
-	MaxResults int     `json:"maxResults" structs:"maxResults"`
-	Total      int     `json:"total" structs:"total"`
+	Issues        []Issue `json:"issues" structs:"issues"`
+	StartAt       int     `json:"startAt" structs:"startAt"`
+	MaxResults    int     `json:"maxResults" structs:"maxResults"`
+	Total         int     `json:"total" structs:"total"`
+	NextPageToken string  `json:"nextPageToken" structs:"nextPageToken"`
 }
 
 // GetQueryOptions specifies the optional parameters for the Get Issue methods
@@ -614,16 +635,155 @@ func (s *IssueService) AddLink(issueLink *IssueLink) (*Response, error) {
 	return resp, err
 }
 
+// GroupBySubtaskParent groups the sub-tasks among issues by the key of their parent issue.
+// The parent itself does not have to be among issues. Issues without a parent are not included.
+// The parent link is only available if the "parent" field was requested, e.g. with IssueListOptions.Fields.
+func GroupBySubtaskParent(issues []Issue) map[string][]Issue {
+	subtasks := make(map[string][]Issue)
+	for _, issue := range issues {
+		if issue.Fields == nil || issue.Fields.Parent == nil || issue.Fields.Parent.Key == "" {
+			continue
+		}
+		parent := issue.Fields.Parent.Key
+		subtasks[parent] = append(subtasks[parent], issue)
+	}
+	return subtasks
+}
+
+// NormalizeJQL removes leading and trailing whitespace (e.g. newlines of a query copied from the UI) from jql.
+// If collapseWhitespace is true, every run of whitespace outside of quoted values is replaced by a single space as well.
+// Quoted values are never modified.
+func NormalizeJQL(jql string, collapseWhitespace bool) string {
+	jql = strings.TrimSpace(jql)
+	if !collapseWhitespace {
+		return jql
+	}
+
+	var b bytes.Buffer
+	var quote rune
+	escaped, inSpace := false, false
+	for _, r := range jql {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case unicode.IsSpace(r):
+			if !inSpace {
+				b.WriteRune(' ')
+			}
+			inSpace = true
+			continue
+		case r == '"' || r == '\'':
+			quote = r
+		}
+		inSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// validateJQLClause checks that clause can be combined with other JQL by AND.
+// Quotes and parentheses have to be balanced and the clause may not contain an ORDER BY.
+func validateJQLClause(clause string) error {
+	outside, terminated := unquotedJQL(clause)
+	if !terminated {
+		return fmt.Errorf("Unterminated quote in JQL %q", clause)
+	}
+
+	depth := 0
+	for _, r := range outside {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("Unbalanced parentheses in JQL %q", clause)
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("Unbalanced parentheses in JQL %q", clause)
+	}
+	if hasOrderBy(clause) {
+		return fmt.Errorf("JQL %q can not be combined, it contains an ORDER BY", clause)
+	}
+	return nil
+}
+
+// orderByField matches a single field of a JQL ORDER BY clause: a field name, a custom field reference (cf[10002])
+// or a quoted field name, optionally followed by a sort direction
+const orderByField = `(?:[a-z_][a-z0-9_.]*|cf\[[0-9]+\]|"[^"\\]*")(?:\s+(?:asc|desc))?`
+
+var (
+	orderByPattern       = regexp.MustCompile(`(?i)^` + orderByField + `(?:\s*,\s*` + orderByField + `)*$`)
+	orderByPrefixPattern = regexp.MustCompile(`(?i)^order\s+by\s+`)
+)
+
+// validateOrderBy checks that orderBy only lists fields to order by, so it can safely be appended to JQL.
+// A leading "ORDER BY" is removed. The fields of the clause are returned.
+func validateOrderBy(orderBy string) (string, error) {
+	fields := orderByPrefixPattern.ReplaceAllString(strings.TrimSpace(orderBy), "")
+	if !orderByPattern.MatchString(fields) {
+		return "", fmt.Errorf("Invalid ORDER BY clause %q, expected a comma separated list of fields with optional ASC or DESC", orderBy)
+	}
+	return fields, nil
+}
+
+// unquotedJQL returns jql without its quoted strings. terminated is false if the last quote is not closed.
+func unquotedJQL(jql string) (outside string, terminated bool) {
+	var b bytes.Buffer
+	var quote rune
+	escaped := false
+	for _, r := range jql {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), quote == 0
+}
+
+// hasOrderBy reports whether jql contains an ORDER BY clause outside of quoted strings.
+func hasOrderBy(jql string) bool {
+	outside, _ := unquotedJQL(jql)
+	return strings.Contains(strings.ToUpper(NormalizeJQL(outside, true)), "ORDER BY")
+}
+
 // Search will search for tickets according to the jql
 //
 // JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/jira-rest-apis/jira-rest-api-tutorials/jira-rest-api-example-query-issues
 func (s *IssueService) Search(jql string, options *SearchOptions) ([]Issue, *Response, error) {
+	jql = s.client.normalizeJQL(jql)
 	var u string
 	if options == nil {
 		u = fmt.Sprintf("rest/api/2/search?jql=%s", url.QueryEscape(jql))
 	} else {
 		u = fmt.Sprintf("rest/api/2/search?jql=%s&startAt=%d&maxResults=%d&expand=%s", url.QueryEscape(jql),
 			options.StartAt, options.MaxResults, options.Expand)
+		extra := options.ExtraParams
+		if options.NextPageToken != "" {
+			extra = url.Values{"nextPageToken": {options.NextPageToken}}
+			mergeExtraParams(extra, options.ExtraParams)
+		}
+		u = withExtraParams(u, extra)
 	}
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -636,6 +796,21 @@ func (s *IssueService) Search(jql string, options *SearchOptions) ([]Issue, *Res
 	return v.Issues, resp, err
 }
 
+// GetMyRecentIssues returns the issues the current user viewed recently, most recently viewed first.
+// If the user has no view history, an empty list is returned.
+//
+// JIRA API docs: https://confluence.atlassian.com/jirasoftwarecloud/advanced-searching-functions-reference-764478342.html#Advancedsearching-functionsreference-issueHistoryissueHistory()
+func (s *IssueService) GetMyRecentIssues(options *SearchOptions) ([]Issue, *Response, error) {
+	issues, resp, err := s.Search("issuekey in issueHistory() ORDER BY lastViewed DESC", options)
+	if err != nil {
+		return nil, resp, err
+	}
+	if issues == nil {
+		issues = []Issue{}
+	}
+	return issues, resp, nil
+}
+
 // GetCustomFields returns a map of customfield_* keys with string values
 func (s *IssueService) GetCustomFields(issueID string) (CustomFields, *Response, error) {
 	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s", issueID)
@@ -673,7 +848,7 @@ func (s *IssueService) GetCustomFields(issueID string) (CustomFields, *Response,
 }
 
 // GetTransitions gets a list of the transitions possible for this issue by the current user,
-// along with fields that are required and their types.
+// along with the status each transition leads to and fields that are required and their types.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/latest/#api/2/issue-getTransitions
 func (s *IssueService) GetTransitions(id string) ([]Transition, *Response, error) {
@@ -689,10 +864,22 @@ func (s *IssueService) GetTransitions(id string) ([]Transition, *Response, error
 }
 
 // DoTransition performs a transition on an issue.
-// When performing the transition you can update or set other issue fields.
+// To update or set other issue fields while performing the transition, use DoTransitionWithFields.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/latest/#api/2/issue-doTransition
 func (s *IssueService) DoTransition(ticketID, transitionID string) (*Response, error) {
+	return s.DoTransitionWithFields(ticketID, transitionID, nil)
+}
+
+// DoTransitionWithFields performs a transition on an issue and sets the given fields, keyed by field Id.
+// Only fields present on the transition screen can be set (see GetTransitions).
+// A string value for the key "comment" is added as a comment to the issue instead of being set as a field.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/latest/#api/2/issue-doTransition
+func (s *IssueService) DoTransitionWithFields(ticketID, transitionID string, fields map[string]interface{}) (*Response, error) {
+	if transitionID == "" {
+		return nil, fmt.Errorf("No transition given for issue %s", ticketID)
+	}
 	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s/transitions", ticketID)
 
 	payload := CreateTransitionPayload{
@@ -700,6 +887,19 @@ func (s *IssueService) DoTransition(ticketID, transitionID string) (*Response, e
 			ID: transitionID,
 		},
 	}
+	for id, value := range fields {
+		if comment, ok := value.(string); ok && id == "comment" {
+			payload.Update = map[string]interface{}{
+				"comment": []map[string]interface{}{{"add": map[string]string{"body": comment}}},
+			}
+			continue
+		}
+		if payload.Fields == nil {
+			payload.Fields = make(map[string]interface{})
+		}
+		payload.Fields[id] = value
+	}
+
 	req, err := s.client.NewRequest("POST", apiEndpoint, payload)
 	if err != nil {
 		return nil, err
@@ -801,3 +1001,46 @@ func (s *IssueService) Delete(issueID string) (*Response, error) {
 	resp, err := s.client.Do(req, nil)
 	return resp, err
 }
+
+// IssueUpdateResult is the outcome of updating a single issue with BulkUpdateIssues
+type IssueUpdateResult struct {
+	Key string
+	// Err is nil if the issue was updated
+	Err error
+}
+
+// BulkUpdateIssues sets the same fields on all issues, given their keys, e.g. a fix version after sprint planning.
+// Up to concurrency issues are updated in parallel, if concurrency is less than 1 maxConcurrentRequests is used.
+// Once ctx is done, no further updates are started and the remaining issues fail with the error of ctx.
+// A result is returned for every issue, in the order of issueKeys. If any update failed, an error summarizing
+// the failed issues is returned as well.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/issue-editIssue
+func (s *IssueService) BulkUpdateIssues(ctx context.Context, issueKeys []string, fields map[string]interface{}, concurrency int) ([]IssueUpdateResult, error) {
+	payload := map[string]interface{}{"fields": fields}
+	results := make([]IssueUpdateResult, len(issueKeys))
+	parallelize(len(issueKeys), concurrency, func(i int) {
+		results[i].Key = issueKeys[i]
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			return
+		}
+		req, err := s.client.NewRequest("PUT", fmt.Sprintf("rest/api/2/issue/%s", issueKeys[i]), payload)
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		_, results[i].Err = s.client.Do(req.WithContext(ctx), nil)
+	})
+
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", result.Key, result.Err))
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("Could not update %d of %d issues: %s", len(failed), len(issueKeys), strings.Join(failed, ", "))
+	}
+	return results, nil
+}
@@ -1,12 +1,14 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/trivago/tgo/tcontainer"
@@ -372,6 +374,68 @@ func TestIssueService_Search(t *testing.T) {
 	}
 }
 
+func TestIssueService_GetMyRecentIssues(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("jql"), "issuekey in issueHistory() ORDER BY lastViewed DESC"; got != want {
+			t.Errorf("Expected JQL %q. Got %q", want, got)
+		}
+		fmt.Fprint(w, `{"startAt": 0,"maxResults": 50,"total": 1,"issues": [{"id": "10230","key": "BULK-62","fields": {"summary": "testing"}}]}`)
+	})
+
+	issues, _, err := testClient.Issue.GetMyRecentIssues(nil)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "BULK-62" {
+		t.Errorf("Expected recent issue BULK-62. Got %+v", issues)
+	}
+}
+
+func TestIssueService_GetMyRecentIssues_EmptyHistory(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt": 0,"maxResults": 50,"total": 0,"issues": []}`)
+	})
+
+	issues, _, err := testClient.Issue.GetMyRecentIssues(nil)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if issues == nil || len(issues) != 0 {
+		t.Errorf("Expected an empty issue list. Got %+v", issues)
+	}
+}
+
+func TestIssueService_Search_TrimsJQL(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/search?jql=project+%3D+AR")
+		fmt.Fprint(w, `{"startAt": 0,"maxResults": 50,"total": 0,"issues": []}`)
+	})
+
+	if _, _, err := testClient.Issue.Search("project = AR\n", nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestNormalizeJQL(t *testing.T) {
+	jql := "  project = AR\n\tAND   summary ~ \"two  spaces\"  AND labels = 'a\\'  b'\r\n"
+
+	if got, want := NormalizeJQL(jql, false), "project = AR\n\tAND   summary ~ \"two  spaces\"  AND labels = 'a\\'  b'"; got != want {
+		t.Errorf("NormalizeJQL without collapsing is %q, want %q", got, want)
+	}
+	if got, want := NormalizeJQL(jql, true), "project = AR AND summary ~ \"two  spaces\" AND labels = 'a\\'  b'"; got != want {
+		t.Errorf("NormalizeJQL with collapsing is %q, want %q", got, want)
+	}
+}
+
 func TestIssueService_Search_WithoutPaging(t *testing.T) {
 	setup()
 	defer teardown()
@@ -480,6 +544,13 @@ func TestIssueService_GetTransitions(t *testing.T) {
 	if transitions[0].Fields["summary"].Required != false {
 		t.Errorf("First transition summary field should not be required")
 	}
+
+	if transitions[0].To.ID != "10000" || transitions[0].To.Name != "In Progress" {
+		t.Errorf("Expected first transition to lead to In Progress. Got %+v", transitions[0].To)
+	}
+	if transitions[1].ID != "711" || transitions[1].To.ID != "5" || transitions[1].To.Name != "Closed" {
+		t.Errorf("Expected second transition to lead to Closed. Got %s -> %+v", transitions[1].ID, transitions[1].To)
+	}
 }
 
 func TestIssueService_DoTransition(t *testing.T) {
@@ -512,6 +583,47 @@ func TestIssueService_DoTransition(t *testing.T) {
 	}
 }
 
+func TestIssueService_DoTransitionWithFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/api/2/issue/123/transitions"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		want := map[string]interface{}{
+			"transition": map[string]interface{}{"id": "5"},
+			"fields": map[string]interface{}{
+				"resolution": map[string]interface{}{"name": "Fixed"},
+			},
+			"update": map[string]interface{}{
+				"comment": []interface{}{map[string]interface{}{"add": map[string]interface{}{"body": "Fixed in 1.2"}}},
+			},
+		}
+		if !reflect.DeepEqual(payload, want) {
+			t.Errorf("Expected payload %v. Got %v", want, payload)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fields := map[string]interface{}{
+		"resolution": map[string]string{"name": "Fixed"},
+		"comment":    "Fixed in 1.2",
+	}
+	if _, err := testClient.Issue.DoTransitionWithFields("123", "5", fields); err != nil {
+		t.Errorf("Got error: %v", err)
+	}
+	if _, err := testClient.Issue.DoTransitionWithFields("123", "", nil); err == nil {
+		t.Error("Expected an error for an empty transition. Got none")
+	}
+}
+
 func TestIssueFields_TestMarshalJSON_PopulateUnknownsSuccess(t *testing.T) {
 	data := `{
 			"customfield_123":"test",
@@ -977,3 +1089,86 @@ func TestIssueService_Delete(t *testing.T) {
 		t.Errorf("Error given: %s", err)
 	}
 }
+
+func TestIssueService_BulkUpdateIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	updated := map[string]string{}
+	testMux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		if key == "TEST-2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		updated[key] = string(body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fields := map[string]interface{}{"labels": []string{"planned"}}
+	results, err := testClient.Issue.BulkUpdateIssues(context.Background(), []string{"TEST-1", "TEST-2", "TEST-3"}, fields, 2)
+	if err == nil || !strings.Contains(err.Error(), "Could not update 1 of 3 issues: TEST-2") {
+		t.Errorf("Expected an error for TEST-2. Got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results. Got %d", len(results))
+	}
+	for i, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		if results[i].Key != key {
+			t.Errorf("Expected result %d for %s. Got %s", i, key, results[i].Key)
+		}
+		if failed := results[i].Err != nil; failed != (key == "TEST-2") {
+			t.Errorf("Unexpected result for %s: %v", key, results[i].Err)
+		}
+	}
+	want := `{"fields":{"labels":["planned"]}}` + "\n"
+	if len(updated) != 2 || updated["TEST-1"] != want || updated["TEST-3"] != want {
+		t.Errorf("Expected TEST-1 and TEST-3 to be updated with %q. Got %v", want, updated)
+	}
+}
+
+func TestIssueService_BulkUpdateIssues_CanceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request for %s", r.URL.Path)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := testClient.Issue.BulkUpdateIssues(ctx, []string{"TEST-1", "TEST-2"}, map[string]interface{}{"labels": []string{}}, 1)
+	if err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	for _, result := range results {
+		if result.Err != context.Canceled {
+			t.Errorf("Expected %s to be canceled. Got %v", result.Key, result.Err)
+		}
+	}
+}
+
+func TestIssueService_Search_NextPageToken(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("nextPageToken"); got != "abc" {
+			t.Errorf("Expected nextPageToken abc. Got %q", got)
+		}
+		fmt.Fprint(w, `{"issues":[{"key":"TEST-3"}]}`)
+	})
+
+	issues, resp, err := testClient.Issue.Search("project = TEST", &SearchOptions{MaxResults: 2, NextPageToken: "abc"})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(issues) != 1 || resp.NextPageToken != "" {
+		t.Errorf("Expected the last page with 1 issue. Got %d issues, next page token %q", len(issues), resp.NextPageToken)
+	}
+}
@@ -2,12 +2,22 @@ package jira
 
 import (
 	"bytes"
+	"container/list"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"path"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -23,6 +33,54 @@ type Client struct {
 	// Session storage if the user authentificate with a Session cookie
 	session *Session
 
+	// etags caches GET responses for conditional requests, see EnableETagCache
+	etags *etagCache
+
+	// maxResults holds the page size caps of issue list endpoints, see BoardService.DiscoverMaxResults
+	maxResults maxResultsCache
+
+	// fields caches the field list of the JIRA instance, see BoardService.ResolveEstimationFieldName
+	fields fieldListCache
+
+	// JSON marshals request bodies and unmarshals response bodies.
+	// If nil, encoding/json is used.
+	JSON JSONCodec
+
+	// CollapseJQLWhitespace replaces runs of whitespace in JQL queries by a single space before they are sent.
+	// Leading and trailing whitespace is always removed. See NormalizeJQL.
+	CollapseJQLWhitespace bool
+
+	// DefaultPageSizes maps API endpoints to the page size (maxResults) requested from them by GET requests
+	// that do not specify one. Keys are path.Match patterns of the endpoint path relative to the base URL,
+	// e.g. "rest/agile/1.0/board" for the list of boards and "rest/agile/1.0/board/*/issue" for the issues of a board.
+	// If several patterns match, the most specific one wins, i.e. the one with the most characters other than * and ?,
+	// e.g. "rest/agile/1.0/board/1/issue" over "rest/agile/1.0/board/*/issue"; ties are broken by comparing the patterns lexically.
+	// Endpoints without a matching pattern use the default page size of JIRA.
+	DefaultPageSizes map[string]int
+
+	// APIVariant selects the flavour of the REST API the Client talks to.
+	// Methods use the paths of JIRA Server, which are rewritten for other variants. Defaults to APIVariantServer.
+	APIVariant APIVariant
+
+	// AcceptLanguage is sent as the Accept-Language header of every request created by NewRequest, if set,
+	// e.g. "de-DE" to get translated names of statuses and fields. Use WithAcceptLanguage for a single request.
+	AcceptLanguage string
+
+	// UserAgent is sent as the User-Agent header of every request, e.g. to identify an integration in the access logs of JIRA.
+	// NewClient sets it to defaultUserAgent, use WithUserAgent to override it.
+	UserAgent string
+
+	// BoardAdminGroups are the groups whose members may administer boards, see BoardService.CanAdministerBoards.
+	// Defaults to defaultBoardAdminGroups.
+	BoardAdminGroups []string
+
+	// RetryPolicy controls the retries of failed requests. If nil, requests are not retried.
+	RetryPolicy *RetryPolicy
+
+	// Logger receives a debug message for every request sent and an error message for every failed request.
+	// If nil, nothing is logged.
+	Logger Logger
+
 	// Services used for talking to different parts of the JIRA API.
 	Authentication *AuthenticationService
 	Issue          *IssueService
@@ -32,6 +90,112 @@ type Client struct {
 	User           *UserService
 	Group          *GroupService
 	Webhook        *WebhookService
+	Field          *FieldService
+	Filter         *FilterService
+}
+
+// APIVariant identifies a flavour of the JIRA REST API
+type APIVariant string
+
+const (
+	// APIVariantServer is the REST API of JIRA Server, version 2
+	APIVariantServer APIVariant = "server"
+	// APIVariantCloud is the REST API of JIRA Cloud. Endpoints whose payloads are the same in version 2 and 3 use version 3,
+	// all others stay on version 2, see apiPathRewrites.
+	APIVariantCloud APIVariant = "cloud"
+)
+
+// apiPathRewrites lists per API variant the endpoints of JIRA Server and their replacement.
+// Paths are relative to the base URL, without a leading slash. A rewrite applies to the endpoint itself,
+// its sub paths and its query strings.
+// Version 3 of the Cloud API returns rich text such as issue descriptions, comments and environments
+// in the Atlassian Document Format instead of strings, so only endpoints without rich text are rewritten.
+var apiPathRewrites = map[APIVariant][][2]string{
+	APIVariantCloud: {
+		{"rest/api/2/field", "rest/api/3/field"},
+		{"rest/api/2/group", "rest/api/3/group"},
+		{"rest/api/2/myself", "rest/api/3/myself"},
+		{"rest/api/2/user", "rest/api/3/user"},
+	},
+}
+
+// resolvePath rewrites the JIRA Server path urlStr for the APIVariant of the Client.
+// Absolute URLs and paths without a known difference are returned unchanged.
+func (c *Client) resolvePath(urlStr string) string {
+	relative := strings.TrimPrefix(urlStr, "/")
+	for _, rewrite := range apiPathRewrites[c.APIVariant] {
+		if !strings.HasPrefix(relative, rewrite[0]) {
+			continue
+		}
+		rest := strings.TrimPrefix(relative, rewrite[0])
+		if rest == "" || rest[0] == '/' || rest[0] == '?' {
+			return urlStr[:len(urlStr)-len(relative)] + rewrite[1] + rest
+		}
+	}
+	return urlStr
+}
+
+// Logger is the interface of the logger used by the Client, see Client.Logger.
+// Its methods follow the signature of fmt.Printf.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// noopLogger is the default Logger, which discards all messages
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Errorf(format string, args ...interface{}) {}
+
+// logger returns the Logger of the Client, falling back to a Logger that discards all messages.
+func (c *Client) logger() Logger {
+	if c.Logger == nil {
+		return noopLogger{}
+	}
+	return c.Logger
+}
+
+// JSONCodec marshals and unmarshals JSON.
+// It can be used to replace encoding/json by a faster, compatible implementation.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// stdJSONCodec is the default JSONCodec, based on encoding/json
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// jsonCodec returns the JSONCodec of the Client, falling back to encoding/json.
+func (c *Client) jsonCodec() JSONCodec {
+	if c.JSON == nil {
+		return stdJSONCodec{}
+	}
+	return c.JSON
+}
+
+// libraryVersion is the version of this library, sent as part of the default User-Agent
+const libraryVersion = "1.0.0"
+
+// defaultUserAgent is the User-Agent of a Client, unless it is set with WithUserAgent
+const defaultUserAgent = "go-jira/" + libraryVersion
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithUserAgent sets the User-Agent header sent with every request of the Client.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
 }
 
 // NewClient returns a new JIRA API client.
@@ -41,7 +205,8 @@ type Client struct {
 // As an alternative you can use Session Cookie based authentication provided by this package as well.
 // See https://docs.atlassian.com/jira/REST/latest/#authentication
 // baseURL is the HTTP endpoint of your JIRA instance and should always be specified with a trailing slash.
-func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+// The given options are applied after the defaults have been set.
+func NewClient(httpClient *http.Client, baseURL string, options ...ClientOption) (*Client, error) {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
@@ -52,8 +217,9 @@ func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
 	}
 
 	c := &Client{
-		client:  httpClient,
-		baseURL: parsedBaseURL,
+		client:    httpClient,
+		baseURL:   parsedBaseURL,
+		UserAgent: defaultUserAgent,
 	}
 	c.Authentication = &AuthenticationService{client: c}
 	c.Issue = &IssueService{client: c}
@@ -63,16 +229,93 @@ func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
 	c.User = &UserService{client: c}
 	c.Group = &GroupService{client: c}
 	c.Webhook = &WebhookService{client: c}
+	c.Field = &FieldService{client: c}
+	c.Filter = &FilterService{client: c}
+
+	for _, option := range options {
+		option(c)
+	}
 
 	return c, nil
 }
 
+// RequestOption customizes a single API request created by NewRequest.
+type RequestOption func(*http.Request)
+
+// WithAccept sets the Accept header of the request.
+// This overrides the default of application/json for endpoints that offer (or require) a different representation.
+func WithAccept(accept string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Accept", accept)
+	}
+}
+
+// WithContentType sets the Content-Type header of the request.
+// It is meant for bodies passed as io.Reader, e.g. images or multipart forms, which are sent as application/json otherwise.
+// Bodies that NewRequest encodes itself already carry the matching content type.
+func WithContentType(contentType string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Content-Type", contentType)
+	}
+}
+
+// WithAcceptLanguage sets the Accept-Language header of the request, overriding Client.AcceptLanguage.
+// JIRA returns some display strings, e.g. names of statuses and fields, in the requested language.
+func WithAcceptLanguage(language string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Accept-Language", language)
+	}
+}
+
+// WithTimeout aborts the request if it, including reading the response body, takes longer than d.
+// It is meant for callers that do not manage a context.Context themselves.
+// The timeout is applied by Client.Do and survives replacing the context of the request with req.WithContext.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(timeoutHeader, d.String())
+	}
+}
+
+// timeoutHeader carries the timeout set by WithTimeout from NewRequest to Client.Do.
+// Unlike a context value, a header is kept by req.WithContext. Client.Do removes it before the request is sent.
+const timeoutHeader = "X-Go-Jira-Timeout"
+
+// requestTimeout returns the timeout set by WithTimeout for req, 0 if there is none.
+func requestTimeout(req *http.Request) time.Duration {
+	timeout, _ := time.ParseDuration(req.Header.Get(timeoutHeader))
+	return timeout
+}
+
+// withoutTimeoutHeader returns a shallow copy of req without the header set by WithTimeout.
+// The headers are copied, so req itself is not modified.
+func withoutTimeoutHeader(req *http.Request, ctx context.Context) *http.Request {
+	r := req.WithContext(ctx)
+	r.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		if key != timeoutHeader {
+			r.Header[key] = values
+		}
+	}
+	return r
+}
+
+// cancelOnClose releases the context of a request once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
 // NewRawRequest creates an API request.
 // A relative URL can be provided in urlStr, in which case it is resolved relative to the baseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.
 // Allows using an optional native io.Reader for sourcing the request body.
 func (c *Client) NewRawRequest(method, urlStr string, body io.Reader) (*http.Request, error) {
-	rel, err := url.Parse(urlStr)
+	rel, err := url.Parse(c.resolvePath(urlStr))
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +328,7 @@ func (c *Client) NewRawRequest(method, urlStr string, body io.Reader) (*http.Req
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	c.setUserAgent(req)
 
 	// Set authentication information
 	if c.Authentication.authType == authTypeSession {
@@ -104,33 +348,65 @@ func (c *Client) NewRawRequest(method, urlStr string, body io.Reader) (*http.Req
 	return req, nil
 }
 
+// setUserAgent sets the User-Agent header of req to Client.UserAgent, if set.
+func (c *Client) setUserAgent(req *http.Request) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+}
+
 // NewRequest creates an API request.
 // A relative URL can be provided in urlStr, in which case it is resolved relative to the baseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.
 // If specified, the value pointed to by body is JSON encoded and included as the request body.
-func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
-	rel, err := url.Parse(urlStr)
+// There are two exceptions: An io.Reader is used as the request body as it is
+// and url.Values are sent form-encoded (application/x-www-form-urlencoded).
+// The Content-Type of io.Reader bodies defaults to application/json, use WithContentType to override it.
+// The given options are applied after the default headers have been set.
+func (c *Client) NewRequest(method, urlStr string, body interface{}, options ...RequestOption) (*http.Request, error) {
+	rel, err := url.Parse(c.resolvePath(urlStr))
 	if err != nil {
 		return nil, err
 	}
 
 	u := c.baseURL.ResolveReference(rel)
+	if method == "GET" {
+		c.applyDefaultPageSize(u)
+	}
 
-	var buf io.ReadWriter
-	if body != nil {
-		buf = new(bytes.Buffer)
-		err = json.NewEncoder(buf).Encode(body)
+	var buf io.Reader
+	var payload []byte
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		buf = b
+	case url.Values:
+		payload = []byte(b.Encode())
+		buf = bytes.NewReader(payload)
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		payload, err = c.jsonCodec().Marshal(body)
 		if err != nil {
 			return nil, err
 		}
+		// Keep the trailing newline json.Encoder has always written
+		payload = append(payload, '\n')
+		buf = bytes.NewReader(payload)
 	}
 
+	// http.NewRequest sets GetBody for the *bytes.Reader of encoded bodies, which allows retries to replay them
 	req, err := http.NewRequest(method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	if c.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", c.AcceptLanguage)
+	}
+	c.setUserAgent(req)
 
 	// Set authentication information
 	if c.Authentication.authType == authTypeSession {
@@ -147,6 +423,10 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		}
 	}
 
+	for _, option := range options {
+		option(req)
+	}
+
 	return req, nil
 }
 
@@ -167,17 +447,236 @@ func addOptions(s string, opt interface{}) (string, error) {
 	if err != nil {
 		return s, err
 	}
+	if o, ok := opt.(extraParamsOptions); ok {
+		mergeExtraParams(qs, o.extraParams())
+	}
 
 	u.RawQuery = qs.Encode()
 	return u.String(), nil
 }
 
+// extraParamsOptions is implemented by all options embedding SearchOptions
+type extraParamsOptions interface {
+	extraParams() url.Values
+}
+
+// mergeExtraParams adds all extra parameters to qs that are not set in qs already.
+func mergeExtraParams(qs url.Values, extra url.Values) {
+	for key, values := range extra {
+		if _, ok := qs[key]; !ok {
+			qs[key] = append([]string(nil), values...)
+		}
+	}
+}
+
+// withExtraParams adds the extra parameters to the query string of the URL s, see mergeExtraParams.
+// s is returned unchanged if it can not be parsed.
+func withExtraParams(s string, extra url.Values) string {
+	if len(extra) == 0 {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	qs := u.Query()
+	mergeExtraParams(qs, extra)
+	u.RawQuery = qs.Encode()
+	return u.String()
+}
+
+// normalizeJQL prepares jql to be sent to JIRA, according to the configuration of the Client.
+func (c *Client) normalizeJQL(jql string) string {
+	return NormalizeJQL(jql, c.CollapseJQLWhitespace)
+}
+
+// maxConcurrentRequests is the default number of requests the aggregating helpers send in parallel.
+const maxConcurrentRequests = 5
+
+// parallelize calls fn for every index in [0, n) and runs at most concurrency calls at the same time.
+// A concurrency lower than 1 falls back to maxConcurrentRequests.
+func parallelize(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = maxConcurrentRequests
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// parallelizeContext calls fn for every index in [0, n) like parallelize, but stops starting calls
+// once ctx is done or a call has failed. The context passed to fn is canceled on the first failure,
+// which aborts the requests still running. The error of the first failed call is returned,
+// or the error of ctx if it ended before all calls were made.
+func parallelizeContext(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var first error
+	parallelize(n, concurrency, func(i int) {
+		if callCtx.Err() != nil {
+			return
+		}
+		if err := fn(callCtx, i); err != nil {
+			once.Do(func() {
+				first = err
+				cancel()
+			})
+		}
+	})
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
+
+// firstError returns the first non-nil error of errs.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDefaultPageSize adds the maxResults parameter configured in DefaultPageSizes to u,
+// unless u already specifies one.
+func (c *Client) applyDefaultPageSize(u *url.URL) {
+	if len(c.DefaultPageSizes) == 0 {
+		return
+	}
+	values := u.Query()
+	if _, ok := values["maxResults"]; ok {
+		return
+	}
+
+	if size, ok := c.defaultPageSize(u.Path); ok {
+		values.Set("maxResults", strconv.Itoa(size))
+		u.RawQuery = values.Encode()
+	}
+}
+
+// defaultPageSize returns the page size configured in DefaultPageSizes for the endpoint urlPath.
+// urlPath may be absolute or relative to the base URL. Of several matching patterns the most specific one is used,
+// so the result does not depend on the iteration order of the map.
+func (c *Client) defaultPageSize(urlPath string) (int, bool) {
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(urlPath, c.baseURL.Path), "/")
+	best, found := "", false
+	for pattern := range c.DefaultPageSizes {
+		if matched, _ := path.Match(strings.TrimPrefix(pattern, "/"), endpoint); !matched {
+			continue
+		}
+		if !found || patternSpecificity(pattern) > patternSpecificity(best) ||
+			(patternSpecificity(pattern) == patternSpecificity(best) && pattern < best) {
+			best, found = pattern, true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return c.DefaultPageSizes[best], true
+}
+
+// patternSpecificity returns the number of characters of a path.Match pattern that are not wildcards
+func patternSpecificity(pattern string) int {
+	return len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+}
+
+// issueListURL returns the URL of a request to the issue list endpoint apiEndpoint with the given options.
+// The JQL is normalized and combined with the requested order and, if no page size is given or configured in
+// DefaultPageSizes, the page size discovered by BoardService.DiscoverMaxResults is used.
+// options is not modified; a nil options is allowed.
+func (c *Client) issueListURL(apiEndpoint string, options *IssueListOptions) (string, error) {
+	opt := IssueListOptions{}
+	if options != nil {
+		opt = *options
+	}
+	opt.JQL = c.normalizeJQL(opt.JQL)
+	switch {
+	case opt.OrderBy != "":
+		orderBy, err := validateOrderBy(opt.OrderBy)
+		if err != nil {
+			return "", err
+		}
+		if hasOrderBy(opt.JQL) {
+			return "", fmt.Errorf("JQL %q has an ORDER BY clause already, OrderBy %q can not be added", opt.JQL, opt.OrderBy)
+		}
+		opt.JQL = strings.TrimSpace(opt.JQL + " ORDER BY " + orderBy)
+	case opt.OrderByRank && !hasOrderBy(opt.JQL):
+		opt.JQL = strings.TrimSpace(opt.JQL + " ORDER BY Rank ASC")
+	}
+	if opt.MaxResults == 0 {
+		if _, ok := c.defaultPageSize(apiEndpoint); !ok {
+			opt.MaxResults = c.maxResults.get(apiEndpoint)
+		}
+	}
+	return addOptions(apiEndpoint, &opt)
+}
+
+// maxResultsCache holds the largest page size JIRA accepts per issue list endpoint, keyed by the endpoint path
+type maxResultsCache struct {
+	mu     sync.RWMutex
+	values map[string]int
+}
+
+// get returns the page size cap of the endpoint apiEndpoint, 0 if unknown
+func (m *maxResultsCache) get(apiEndpoint string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.values[apiEndpoint]
+}
+
+func (m *maxResultsCache) set(apiEndpoint string, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.values == nil {
+		m.values = make(map[string]int)
+	}
+	m.values[apiEndpoint] = value
+}
+
+// fieldListCache holds the system and custom fields of the JIRA instance, nil if not fetched yet
+type fieldListCache struct {
+	mu     sync.RWMutex
+	fields []Field
+}
+
+func (f *fieldListCache) get() []Field {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fields
+}
+
+func (f *fieldListCache) set(fields []Field) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fields = fields
+}
+
+// InvalidateFieldCache drops the field list cached by BoardService.ResolveEstimationFieldName,
+// e.g. after custom fields have been created or renamed. The next call fetches the field list again.
+func (c *Client) InvalidateFieldCache() {
+	c.fields.set(nil)
+}
+
 // NewMultiPartRequest creates an API request including a multi-part file.
 // A relative URL can be provided in urlStr, in which case it is resolved relative to the baseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.
 // If specified, the value pointed to by buf is a multipart form.
 func (c *Client) NewMultiPartRequest(method, urlStr string, buf *bytes.Buffer) (*http.Request, error) {
-	rel, err := url.Parse(urlStr)
+	rel, err := url.Parse(c.resolvePath(urlStr))
 	if err != nil {
 		return nil, err
 	}
@@ -191,6 +690,7 @@ func (c *Client) NewMultiPartRequest(method, urlStr string, buf *bytes.Buffer) (
 
 	// Set required headers
 	req.Header.Set("X-Atlassian-Token", "nocheck")
+	c.setUserAgent(req)
 
 	// Set authentication information
 	if c.Authentication.authType == authTypeSession {
@@ -213,39 +713,384 @@ func (c *Client) NewMultiPartRequest(method, urlStr string, buf *bytes.Buffer) (
 // Do sends an API request and returns the API response.
 // The API response is JSON decoded and stored in the value pointed to by v, or returned as an error if an API error has occurred.
 func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
-	httpResp, err := c.client.Do(req)
+	if _, ok := req.Header[timeoutHeader]; !ok {
+		return c.do(req, v)
+	}
+	timeout := requestTimeout(req)
+	if timeout <= 0 {
+		return c.do(withoutTimeoutHeader(req, req.Context()), v)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := c.do(withoutTimeoutHeader(req, ctx), v)
+	if err == nil && v == nil && resp != nil && resp.Response != nil {
+		// The caller reads the body, so the timeout ends when it is closed
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+	cancel()
+	return resp, err
+}
+
+// do sends an API request and returns the API response, see Do.
+func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
+	var cached *etagEntry
+	if c.etags != nil && v != nil && req.Method == "GET" {
+		cached = c.etags.get(req.URL.String())
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
+	httpResp, err := c.send(req)
 	if err != nil {
+		c.logger().Errorf("%s %s failed: %s", req.Method, req.URL, err)
 		return nil, err
 	}
 
+	if cached != nil && httpResp.StatusCode == http.StatusNotModified {
+		httpResp.Body.Close()
+		err = c.jsonCodec().Unmarshal(cached.body, v)
+		resp := newResponse(httpResp, v)
+		resp.Warnings = parseWarnings(cached.body)
+		return resp, err
+	}
+
 	err = CheckResponse(httpResp)
 	if err != nil {
+		c.logger().Errorf("%s %s failed: %s", req.Method, req.URL, err)
 		// Even though there was an error, we still return the response
 		// in case the caller wants to inspect it further
 		return newResponse(httpResp, nil), err
 	}
 
+	var data []byte
 	if v != nil {
-		// Open a NewDecoder and defer closing the reader only if there is a provided interface to decode to
+		// Read and close the body only if there is a provided interface to decode to
 		defer httpResp.Body.Close()
-		err = json.NewDecoder(httpResp.Body).Decode(v)
+		data, err = ioutil.ReadAll(httpResp.Body)
+		if err == nil {
+			err = c.jsonCodec().Unmarshal(data, v)
+		}
+		if err == nil && c.etags != nil && req.Method == "GET" {
+			if etag := httpResp.Header.Get("ETag"); etag != "" {
+				c.etags.set(req.URL.String(), &etagEntry{etag: etag, body: data})
+			}
+		}
 	}
 
 	resp := newResponse(httpResp, v)
+	resp.Warnings = parseWarnings(data)
 	return resp, err
 }
 
+// PageInfo describes a page of a paginated list returned by JIRA
+type PageInfo struct {
+	StartAt    int  `json:"startAt"`
+	MaxResults int  `json:"maxResults"`
+	Total      int  `json:"total"`
+	IsLast     bool `json:"isLast"`
+}
+
+// pagedEnvelope is the envelope of the paginated lists of the JIRA API
+type pagedEnvelope struct {
+	PageInfo
+	Values json.RawMessage `json:"values"`
+}
+
+// DoPaged sends an API request to an endpoint returning a paginated list, i.e. an envelope of the form
+// {"startAt": 0, "maxResults": 50, "total": 100, "isLast": false, "values": [...]}.
+// The values are decoded into v, which should be a pointer to a slice, and the paging information into page (if not nil).
+// The paging information is available in the returned Response as well.
+func (c *Client) DoPaged(req *http.Request, v interface{}, page *PageInfo) (*Response, error) {
+	envelope := new(pagedEnvelope)
+	resp, err := c.Do(req, envelope)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.StartAt = envelope.StartAt
+	resp.MaxResults = envelope.MaxResults
+	resp.Total = envelope.Total
+	if page != nil {
+		*page = envelope.PageInfo
+	}
+	if len(envelope.Values) == 0 {
+		return resp, nil
+	}
+	return resp, c.jsonCodec().Unmarshal(envelope.Values, v)
+}
+
+// RetryPolicy describes when and how often the Client retries a request.
+// The delay between attempts is taken from the Retry-After header of the response, if present.
+// Otherwise it grows exponentially from BaseDelay, with random jitter, up to MaxDelay.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a single request
+	MaxRetries int
+	// RetryStatuses are the status codes of responses that are retried. Defaults to defaultRetryStatuses.
+	RetryStatuses []int
+	// Retryable reports whether a response with the given status code is retried.
+	// If set, it takes precedence over RetryStatuses.
+	Retryable func(statusCode int) bool
+	// RetryNonIdempotent enables retries of all requests, e.g. POST and PATCH.
+	// By default only idempotent requests are retried, see idempotentMethods.
+	RetryNonIdempotent bool
+	// BaseDelay is the delay before the first retry. Defaults to defaultRetryBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. Defaults to defaultRetryMaxDelay.
+	MaxDelay time.Duration
+}
+
+// Default delays of a RetryPolicy
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// defaultRetryStatuses are the status codes retried if RetryPolicy.RetryStatuses is empty.
+// 500 Internal Server Error is not retried, as it usually fails again the same way.
+var defaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// idempotentMethods are the HTTP methods whose requests are retried by default
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// retries reports whether req, having received resp on the given attempt (starting at 0), should be retried.
+func (p *RetryPolicy) retries(req *http.Request, resp *http.Response, attempt int) bool {
+	if p == nil || attempt >= p.MaxRetries {
+		return false
+	}
+	if !idempotentMethods[req.Method] && !p.RetryNonIdempotent {
+		return false
+	}
+	// A body that can not be rewound can not be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(resp.StatusCode)
+	}
+	statuses := p.RetryStatuses
+	if len(statuses) == 0 {
+		statuses = defaultRetryStatuses
+	}
+	for _, status := range statuses {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before retrying after resp, which was received on the given attempt (starting at 0).
+func (p *RetryPolicy) delay(resp *http.Response, attempt int) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return minDuration(time.Duration(seconds)*time.Second, maxDelay)
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return minDuration(maxDuration(time.Until(date), 0), maxDelay)
+		}
+	}
+
+	delay := p.BaseDelay
+	if delay <= 0 {
+		delay = defaultRetryBaseDelay
+	}
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = minDuration(delay, maxDelay)
+	// Jitter spreads the retries of concurrent requests
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// send sends req, retrying it according to the RetryPolicy of the Client.
+// The body of req is rewound before every retry.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		c.logger().Debugf("%s %s", req.Method, req.URL)
+		resp, err := c.client.Do(req)
+		if err != nil || !c.RetryPolicy.retries(req, resp, attempt) {
+			return resp, err
+		}
+
+		delay := c.RetryPolicy.delay(resp, attempt)
+		c.logger().Debugf("%s %s returned %s, retrying in %s (retry %d of %d)", req.Method, req.URL, resp.Status, delay, attempt+1, c.RetryPolicy.MaxRetries)
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// EnableETagCache turns on conditional requests for GET requests with a response body.
+// The ETag and body of every response are cached by URL.
+// Subsequent requests to the same URL send an If-None-Match header and
+// if JIRA answers with 304 Not Modified, the cached body is decoded instead.
+// This is useful for frequently polled resources like board configurations.
+// At most etagCacheSize responses are cached, the least recently used ones are evicted first.
+func (c *Client) EnableETagCache() {
+	if c.etags == nil {
+		c.etags = newETagCache(etagCacheSize)
+	}
+}
+
+// etagCacheSize is the maximum number of responses cached by EnableETagCache
+const etagCacheSize = 1000
+
+// etagEntry is a single cached response
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+// etagCache stores responses by URL and is safe for concurrent use.
+// It holds at most size entries and evicts the least recently used entry when full.
+type etagCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// lru orders the entries by their last use, most recently used first
+	lru *list.List
+}
+
+// etagCacheItem is an element of etagCache.lru
+type etagCacheItem struct {
+	url   string
+	entry *etagEntry
+}
+
+func newETagCache(size int) *etagCache {
+	return &etagCache{size: size, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+func (e *etagCache) get(url string) *etagEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	elem, ok := e.entries[url]
+	if !ok {
+		return nil
+	}
+	e.lru.MoveToFront(elem)
+	return elem.Value.(*etagCacheItem).entry
+}
+
+func (e *etagCache) set(url string, entry *etagEntry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if elem, ok := e.entries[url]; ok {
+		elem.Value.(*etagCacheItem).entry = entry
+		e.lru.MoveToFront(elem)
+		return
+	}
+
+	e.entries[url] = e.lru.PushFront(&etagCacheItem{url: url, entry: entry})
+	for e.lru.Len() > e.size {
+		oldest := e.lru.Back()
+		e.lru.Remove(oldest)
+		delete(e.entries, oldest.Value.(*etagCacheItem).url)
+	}
+}
+
+// Error is returned for API responses with a status code outside the 200 range.
+// ErrorMessages and Errors are parsed from the JSON error body of JIRA, if there is one.
+// Errors maps field names to validation messages, e.g. for failed create or update calls.
+type Error struct {
+	StatusCode    int
+	Method        string
+	URL           string
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// Error returns the status code and all messages of the error.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("Request failed. Status code: %d", e.StatusCode)
+	if e.URL != "" {
+		msg = fmt.Sprintf("Request %s %s failed. Status code: %d", e.Method, e.URL, e.StatusCode)
+	}
+
+	messages := append([]string(nil), e.ErrorMessages...)
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, e.Errors[field]))
+	}
+	if len(messages) > 0 {
+		msg += ". " + strings.Join(messages, ", ")
+	}
+	return msg
+}
+
 // CheckResponse checks the API response for errors, and returns them if present.
-// A response is considered an error if it has a status code outside the 200 range.
-// The caller is responsible to analyze the response body.
+// A response is considered an error if it has a status code outside the 200 range; the returned error is an *Error then.
 // The body can contain JSON (if the error is intended) or xml (sometimes JIRA just failes).
+// It is parsed into the *Error if possible and remains readable for the caller.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 
-	err := fmt.Errorf("Request failed. Please analyze the request body for more details. Status code: %d", r.StatusCode)
-	return err
+	jiraErr := &Error{StatusCode: r.StatusCode}
+	if r.Request != nil {
+		jiraErr.Method = r.Request.Method
+		jiraErr.URL = r.Request.URL.String()
+	}
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if err == nil && len(data) > 0 {
+			// Bodies that are not JSON, e.g. HTML error pages, leave the messages empty
+			json.Unmarshal(data, jiraErr)
+		}
+	}
+	return jiraErr
 }
 
 // GetBaseURL will return you the Base URL.
@@ -262,11 +1107,65 @@ type Response struct {
 	StartAt    int
 	MaxResults int
 	Total      int
+	// NextPageToken is the cursor of the next page for endpoints with cursor based paging, empty on the last page.
+	// Pass it as SearchOptions.NextPageToken to request the next page.
+	NextPageToken string
+
+	// Warnings lists the warningMessages JIRA sent along with the result, e.g. for JQL referring to unknown values.
+	// It is only populated for responses decoded by Client.Do.
+	Warnings []string
+
+	// RateLimitLimit, RateLimitRemaining and RateLimitReset are parsed from the X-RateLimit-* headers of JIRA Cloud.
+	// They are zero if the headers are absent or malformed.
+	RateLimitLimit     int
+	RateLimitRemaining int
+	// RateLimitReset is the time at which the quota is replenished
+	RateLimitReset time.Time
+}
+
+// populateRateLimit sets the rate limit values from the headers of the response.
+// The reset time is accepted as ISO 8601 timestamp and as Unix time in seconds.
+func (r *Response) populateRateLimit() {
+	if r.Response == nil {
+		return
+	}
+	r.RateLimitLimit, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Limit"))
+	r.RateLimitRemaining, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Remaining"))
+
+	reset := r.Header.Get("X-RateLimit-Reset")
+	if reset == "" {
+		return
+	}
+	if seconds, err := strconv.ParseInt(reset, 10, 64); err == nil {
+		r.RateLimitReset = time.Unix(seconds, 0)
+		return
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, reset); err == nil {
+			r.RateLimitReset = t
+			return
+		}
+	}
+}
+
+// parseWarnings returns the warningMessages of a response body, if there are any.
+func parseWarnings(data []byte) []string {
+	if !bytes.Contains(data, []byte(`"warningMessages"`)) {
+		return nil
+	}
+	var body struct {
+		WarningMessages []string `json:"warningMessages"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+	return body.WarningMessages
 }
 
 func newResponse(r *http.Response, v interface{}) *Response {
 	resp := &Response{Response: r}
 	resp.populatePageValues(v)
+	resp.populateRateLimit()
 	return resp
 }
 
@@ -278,6 +1177,12 @@ func (r *Response) populatePageValues(v interface{}) {
 		r.StartAt = value.StartAt
 		r.MaxResults = value.MaxResults
 		r.Total = value.Total
+		r.NextPageToken = value.NextPageToken
+	case *backlogResults:
+		r.StartAt = value.StartAt
+		r.MaxResults = value.MaxResults
+		r.Total = value.Total
+		r.NextPageToken = value.NextPageToken
 	}
 	return
 }
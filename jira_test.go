@@ -2,6 +2,8 @@ package jira
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -112,6 +114,12 @@ func TestNewClient_WithServices(t *testing.T) {
 	if c.Group == nil {
 		t.Error("No GroupService provided")
 	}
+	if c.Field == nil {
+		t.Error("No FieldService provided")
+	}
+	if c.Filter == nil {
+		t.Error("No FilterService provided")
+	}
 }
 
 func TestCheckResponse(t *testing.T) {
@@ -262,6 +270,526 @@ func TestClient_NewRequest_EmptyBody(t *testing.T) {
 	}
 }
 
+func TestClient_NewRequest_ReaderBody(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	inBody := `{"key":"MESOS","raw":true}`
+	req, _ := c.NewRequest("POST", "rest/api/2/issue/", strings.NewReader(inBody))
+
+	// Test that the reader was passed through instead of being JSON encoded
+	body, _ := ioutil.ReadAll(req.Body)
+	if got, want := string(body), inBody; got != want {
+		t.Errorf("NewRequest(%v) Body is %v, want %v", inBody, got, want)
+	}
+}
+
+func TestClient_NewRequest_FormBody(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	inBody := url.Values{"name": []string{"In Review"}, "min": []string{"1"}}
+	req, _ := c.NewRequest("POST", "rest/greenhopper/1.0/rapidviewconfig/columns", inBody)
+
+	body, _ := ioutil.ReadAll(req.Body)
+	if got, want := string(body), "min=1&name=In+Review"; got != want {
+		t.Errorf("NewRequest(%v) Body is %v, want %v", inBody, got, want)
+	}
+	if got, want := req.Header.Get("Content-Type"), "application/x-www-form-urlencoded"; got != want {
+		t.Errorf("Content-Type header is %q, want %q", got, want)
+	}
+}
+
+func TestClient_NewRequest_GetBody(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	inBody, outBody := &Issue{Key: "MESOS"}, `{"key":"MESOS"}`+"\n"
+	req, _ := c.NewRequest("POST", "rest/api/2/issue/", inBody)
+	if req.GetBody == nil {
+		t.Fatal("Expected GetBody to be set")
+	}
+
+	// Consume the original body first, GetBody has to provide a fresh copy anyway
+	ioutil.ReadAll(req.Body)
+	for i := 0; i < 2; i++ {
+		r, err := req.GetBody()
+		if err != nil {
+			t.Errorf("GetBody returned an error: %s", err)
+		}
+		body, _ := ioutil.ReadAll(r)
+		if got, want := string(body), outBody; got != want {
+			t.Errorf("GetBody() Body is %v, want %v", got, want)
+		}
+	}
+}
+
+func TestClient_NewRequest_DefaultAccept(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	req, _ := c.NewRequest("GET", "rest/api/2/issue/", nil)
+	if got, want := req.Header.Get("Accept"), "application/json"; got != want {
+		t.Errorf("Accept header is %q, want %q", got, want)
+	}
+}
+
+func TestClient_NewRequest_WithAccept(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	req, _ := c.NewRequest("GET", "rest/greenhopper/1.0/rapid/charts/cumulativeflowdiagram", nil, WithAccept("text/plain"))
+	if got, want := req.Header.Get("Accept"), "text/plain"; got != want {
+		t.Errorf("Accept header is %q, want %q", got, want)
+	}
+	if got, want := req.Header.Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("Content-Type header is %q, want %q", got, want)
+	}
+}
+
+func TestClient_NewRequest_WithContentType(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	req, _ := c.NewRequest("POST", "rest/api/2/issue", &Issue{Key: "MESOS"})
+	if got, want := req.Header.Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("Content-Type header is %q, want %q", got, want)
+	}
+
+	form := "name=Review&statusId=3"
+	req, _ = c.NewRequest("POST", "rest/greenhopper/1.0/rapidviewconfig/columns", strings.NewReader(form), WithContentType("application/x-www-form-urlencoded"))
+	if got, want := req.Header.Get("Content-Type"), "application/x-www-form-urlencoded"; got != want {
+		t.Errorf("Content-Type header is %q, want %q", got, want)
+	}
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != form {
+		t.Errorf("Request body is %q, want %q", body, form)
+	}
+}
+
+func TestClient_NewRequest_AcceptLanguage(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	req, _ := c.NewRequest("GET", "rest/api/2/status", nil)
+	if got := req.Header.Get("Accept-Language"); got != "" {
+		t.Errorf("Expected no Accept-Language header by default. Got %q", got)
+	}
+
+	c.AcceptLanguage = "de-DE"
+	req, _ = c.NewRequest("GET", "rest/api/2/status", nil)
+	if got, want := req.Header.Get("Accept-Language"), "de-DE"; got != want {
+		t.Errorf("Accept-Language header is %q, want %q", got, want)
+	}
+
+	req, _ = c.NewRequest("GET", "rest/api/2/status", nil, WithAcceptLanguage("fr"))
+	if got, want := req.Header.Get("Accept-Language"), "fr"; got != want {
+		t.Errorf("Accept-Language header is %q, want %q", got, want)
+	}
+}
+
+func TestClient_defaultPageSize_LongestMatch(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.DefaultPageSizes = map[string]int{
+		"rest/agile/1.0/board/*/issue": 100,
+		"rest/agile/1.0/board/1/issue": 20,
+		"rest/agile/1.0/*/*/issue":     30,
+		"rest/agile/1.0/board/*/*":     40,
+	}
+
+	// Run repeatedly, as the iteration order of the map changes between runs
+	for i := 0; i < 20; i++ {
+		if size, _ := testClient.defaultPageSize("rest/agile/1.0/board/1/issue"); size != 20 {
+			t.Fatalf("Expected page size 20 of the exact pattern. Got %d", size)
+		}
+		if size, _ := testClient.defaultPageSize("rest/agile/1.0/board/2/issue"); size != 100 {
+			t.Fatalf("Expected page size 100 of the most specific pattern. Got %d", size)
+		}
+	}
+}
+
+func TestClient_NewRequest_DefaultPageSizes(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.DefaultPageSizes = map[string]int{
+		"rest/agile/1.0/board":           50,
+		"rest/agile/1.0/board/*/issue":   100,
+		"rest/agile/1.0/board/*/backlog": 200,
+	}
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		testRequestURL(t, r, "/rest/agile/1.0/board?maxResults=50")
+		fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+	})
+	var maxResults string
+	testMux.HandleFunc("/rest/agile/1.0/board/1/issue", func(w http.ResponseWriter, r *http.Request) {
+		maxResults = r.URL.Query().Get("maxResults")
+		fmt.Fprint(w, `{"issues":[]}`)
+	})
+
+	if _, _, err := testClient.Board.GetAllBoards(nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if _, _, err := testClient.Board.GetIssuesForBoard(1, nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if maxResults != "100" {
+		t.Errorf("Expected default maxResults 100. Got %q", maxResults)
+	}
+	if _, _, err := testClient.Board.GetIssuesForBoard(1, &IssueListOptions{SearchOptions: SearchOptions{MaxResults: 10}}); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if maxResults != "10" {
+		t.Errorf("Expected requested maxResults 10. Got %q", maxResults)
+	}
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/backlog", func(w http.ResponseWriter, r *http.Request) {
+		maxResults = r.URL.Query().Get("maxResults")
+		fmt.Fprint(w, `{"issues":[]}`)
+	})
+	if _, _, err := testClient.Board.GetIssuesForBacklog("1"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if maxResults != "200" {
+		t.Errorf("Expected default maxResults 200 for the backlog. Got %q", maxResults)
+	}
+
+	req, _ := testClient.NewRequest("POST", "rest/agile/1.0/board", nil)
+	if req.URL.RawQuery != "" {
+		t.Errorf("Expected no default page size for POST requests. Got %q", req.URL.RawQuery)
+	}
+}
+
+func TestClient_Do_WithTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/serverInfo", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			fmt.Fprint(w, `{}`)
+		}
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/serverInfo", nil, WithTimeout(20*time.Millisecond))
+	start := time.Now()
+	_, err := testClient.Do(req, nil)
+	if err == nil {
+		t.Error("Expected a timeout error. Got none")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the request to be aborted after the timeout. It took %s", elapsed)
+	}
+}
+
+func TestClient_Do_WithTimeout_WithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/serverInfo", func(w http.ResponseWriter, r *http.Request) {
+		if timeout := r.Header.Get(timeoutHeader); timeout != "" {
+			t.Errorf("Expected the timeout not to be sent. Got header %q", timeout)
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			fmt.Fprint(w, `{}`)
+		}
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/serverInfo", nil, WithTimeout(20*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req = req.WithContext(ctx)
+	start := time.Now()
+	if _, err := testClient.Do(req, nil); err == nil {
+		t.Error("Expected a timeout error. Got none")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout to survive WithContext. The request took %s", elapsed)
+	}
+}
+
+func TestClient_NewRequest_APIVariant(t *testing.T) {
+	c, err := NewClient(nil, testJIRAInstanceURL)
+	if err != nil {
+		t.Errorf("An error occured. Expected nil. Got %+v.", err)
+	}
+
+	tests := []struct {
+		variant APIVariant
+		urlStr  string
+		want    string
+	}{
+		{"", "rest/api/2/issue/TEST-1", testJIRAInstanceURL + "rest/api/2/issue/TEST-1"},
+		{APIVariantServer, "rest/api/2/issue/TEST-1", testJIRAInstanceURL + "rest/api/2/issue/TEST-1"},
+		{APIVariantCloud, "rest/api/2/issue/TEST-1", testJIRAInstanceURL + "rest/api/2/issue/TEST-1"},
+		{APIVariantCloud, "rest/api/2/search?jql=project%3DTEST", testJIRAInstanceURL + "rest/api/2/search?jql=project%3DTEST"},
+		{APIVariantCloud, "/rest/api/2/user/picker?query=fre", "https://issues.apache.org/rest/api/3/user/picker?query=fre"},
+		{APIVariantCloud, "rest/api/2/user?accountId=1", testJIRAInstanceURL + "rest/api/3/user?accountId=1"},
+		{APIVariantCloud, "rest/api/2/myself", testJIRAInstanceURL + "rest/api/3/myself"},
+		{APIVariantCloud, "rest/api/2/fieldconfiguration", testJIRAInstanceURL + "rest/api/2/fieldconfiguration"},
+		{APIVariantCloud, "rest/agile/1.0/board/1", testJIRAInstanceURL + "rest/agile/1.0/board/1"},
+	}
+	for _, test := range tests {
+		c.APIVariant = test.variant
+		req, _ := c.NewRequest("GET", test.urlStr, nil)
+		if got := req.URL.String(); got != test.want {
+			t.Errorf("%q (%s): expected URL %q. Got %q", test.urlStr, test.variant, test.want, got)
+		}
+	}
+}
+
+func TestClient_APIVariantCloud_IssueGet(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.APIVariant = APIVariantCloud
+
+	// Version 3 returns the description in the Atlassian Document Format, which does not decode into Issue
+	testMux.HandleFunc("/rest/api/3/issue/TEST-1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the issue to be requested from version 2")
+		fmt.Fprint(w, `{"key":"TEST-1","fields":{"description":{"type":"doc","version":1,"content":[]}}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TEST-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"key":"TEST-1","fields":{"description":"Steps to reproduce"}}`)
+	})
+
+	issue, _, err := testClient.Issue.Get("TEST-1", nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if issue.Fields == nil || issue.Fields.Description != "Steps to reproduce" {
+		t.Errorf("Expected description. Got %+v", issue.Fields)
+	}
+}
+
+// recordingLogger is a Logger that records all messages
+type recordingLogger struct {
+	debug []string
+	error []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.error = append(l.error, fmt.Sprintf(format, args...))
+}
+
+func TestClient_Do_Logger(t *testing.T) {
+	setup()
+	defer teardown()
+	logger := new(recordingLogger)
+	testClient.Logger = logger
+
+	testMux.HandleFunc("/rest/api/2/serverInfo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	testMux.HandleFunc("/rest/api/2/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/serverInfo", nil)
+	testClient.Do(req, nil)
+	req, _ = testClient.NewRequest("GET", "rest/api/2/missing", nil)
+	testClient.Do(req, nil)
+
+	want := []string{"GET " + testServer.URL + "/rest/api/2/serverInfo", "GET " + testServer.URL + "/rest/api/2/missing"}
+	if !reflect.DeepEqual(logger.debug, want) {
+		t.Errorf("Expected debug messages %v. Got %v", want, logger.debug)
+	}
+	if len(logger.error) != 1 || !strings.HasPrefix(logger.error[0], "GET "+testServer.URL+"/rest/api/2/missing failed: ") {
+		t.Errorf("Expected an error message for the failed request. Got %v", logger.error)
+	}
+}
+
+func TestClient_Do_Warnings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":0,"issues":[],
+			"warningMessages":["The value 'UNKNOWN' does not exist for the field 'project'."]}`)
+	})
+
+	_, resp, err := testClient.Issue.Search("project = UNKNOWN", nil)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	want := []string{"The value 'UNKNOWN' does not exist for the field 'project'."}
+	if !reflect.DeepEqual(resp.Warnings, want) {
+		t.Errorf("Expected warnings %v. Got %v", want, resp.Warnings)
+	}
+}
+
+func TestClient_DoPaged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"maxResults":2,"startAt":0,"total":3,"isLast":false,"values":[
+			{"id":1,"name":"Team A","type":"scrum"},{"id":2,"name":"Team B","type":"kanban"}]}`)
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/agile/1.0/board", nil)
+	var boards []Board
+	var page PageInfo
+	resp, err := testClient.DoPaged(req, &boards, &page)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(boards) != 2 || boards[1].Name != "Team B" || boards[1].Type != "kanban" {
+		t.Errorf("Unexpected boards: %+v", boards)
+	}
+	if want := (PageInfo{StartAt: 0, MaxResults: 2, Total: 3, IsLast: false}); page != want {
+		t.Errorf("Expected page info %+v. Got %+v", want, page)
+	}
+	if resp.Total != 3 || resp.MaxResults != 2 {
+		t.Errorf("Expected paging info in response. Got %+v", resp)
+	}
+}
+
+func TestClient_Do_RetryPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+	logger := new(recordingLogger)
+	testClient.Logger = logger
+	testClient.RetryPolicy = &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	var bodies []string
+	testMux.HandleFunc("/rest/api/2/issue/TEST-1", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch len(bodies) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	req, _ := testClient.NewRequest("PUT", "rest/api/2/issue/TEST-1", map[string]string{"summary": "Retried"})
+	resp, err := testClient.Do(req, nil)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d. Got %d", http.StatusNoContent, resp.StatusCode)
+	}
+	want := `{"summary":"Retried"}` + "\n"
+	if len(bodies) != 3 || bodies[1] != want || bodies[2] != want {
+		t.Errorf("Expected the body to be sent 3 times. Got %q", bodies)
+	}
+	if len(logger.debug) != 5 || !strings.Contains(logger.debug[1], "429 Too Many Requests, retrying in 0s (retry 1 of 3)") {
+		t.Errorf("Expected retries to be logged. Got %v", logger.debug)
+	}
+}
+
+func TestClient_Do_RetryPolicy_NotForPOST(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.RetryPolicy = &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	calls := 0
+	testMux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := testClient.NewRequest("POST", "rest/api/2/issue", map[string]string{"summary": "Once"})
+	if _, err := testClient.Do(req, nil); err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if calls != 1 {
+		t.Errorf("Expected POST not to be retried. Got %d calls", calls)
+	}
+}
+
+func TestClient_Do_RetryPolicy_NotForPATCH(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.RetryPolicy = &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	calls := 0
+	testMux.HandleFunc("/rest/api/2/issue/TEST-1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := testClient.NewRequest("PATCH", "rest/api/2/issue/TEST-1", map[string]string{"summary": "Once"})
+	if _, err := testClient.Do(req, nil); err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if calls != 1 {
+		t.Errorf("Expected PATCH not to be retried. Got %d calls", calls)
+	}
+}
+
+func TestClient_Do_RetryPolicy_RetryStatuses(t *testing.T) {
+	setup()
+	defer teardown()
+	testClient.RetryPolicy = &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	gatewayCalls := 0
+	testMux.HandleFunc("/rest/api/2/issue/TEST-1", func(w http.ResponseWriter, r *http.Request) {
+		gatewayCalls++
+		if gatewayCalls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	serverErrorCalls := 0
+	testMux.HandleFunc("/rest/api/2/issue/TEST-2", func(w http.ResponseWriter, r *http.Request) {
+		serverErrorCalls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/issue/TEST-1", nil)
+	if _, err := testClient.Do(req, nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if gatewayCalls != 2 {
+		t.Errorf("Expected 502 to be retried once. Got %d calls", gatewayCalls)
+	}
+
+	req, _ = testClient.NewRequest("GET", "rest/api/2/issue/TEST-2", nil)
+	if _, err := testClient.Do(req, nil); err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if serverErrorCalls != 1 {
+		t.Errorf("Expected 500 not to be retried. Got %d calls", serverErrorCalls)
+	}
+
+	testClient.RetryPolicy.RetryStatuses = []int{http.StatusInternalServerError}
+	serverErrorCalls = 0
+	req, _ = testClient.NewRequest("GET", "rest/api/2/issue/TEST-2", nil)
+	testClient.Do(req, nil)
+	if serverErrorCalls != 4 {
+		t.Errorf("Expected configured status 500 to be retried 3 times. Got %d calls", serverErrorCalls)
+	}
+}
+
 func TestClient_NewMultiPartRequest(t *testing.T) {
 	c, err := NewClient(nil, testJIRAInstanceURL)
 	if err != nil {
@@ -346,6 +874,54 @@ func TestClient_Do(t *testing.T) {
 	}
 }
 
+// recordingCodec is a JSONCodec counting how often it was used
+type recordingCodec struct {
+	marshaled, unmarshaled int
+}
+
+func (c *recordingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshaled++
+	return json.Marshal(v)
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshaled++
+	return json.Unmarshal(data, v)
+}
+
+func TestClient_Do_CustomJSONCodec(t *testing.T) {
+	setup()
+	defer teardown()
+
+	type foo struct {
+		A string
+	}
+
+	testMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"A":"b"}`+"\n"; got != want {
+			t.Errorf("Request body is %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"A":"a"}`)
+	})
+
+	codec := new(recordingCodec)
+	testClient.JSON = codec
+
+	req, _ := testClient.NewRequest("POST", "/", &foo{A: "b"})
+	body := new(foo)
+	if _, err := testClient.Do(req, body); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+
+	if body.A != "a" {
+		t.Errorf("Response body = %v, want %v", body.A, "a")
+	}
+	if codec.marshaled != 1 || codec.unmarshaled != 1 {
+		t.Errorf("Expected the codec to be used once in each direction. Got %d marshals and %d unmarshals", codec.marshaled, codec.unmarshaled)
+	}
+}
+
 func TestClient_Do_HTTPResponse(t *testing.T) {
 	setup()
 	defer teardown()
@@ -388,6 +964,45 @@ func TestClient_Do_HTTPError(t *testing.T) {
 	}
 }
 
+func TestClient_Do_HTTPErrorBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	body := `{"errorMessages":["Issue could not be created"],"errors":{"summary":"You must specify a summary of the issue."}}`
+	testMux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, body)
+	})
+
+	req, _ := testClient.NewRequest("POST", "/rest/api/2/issue", &Issue{})
+	resp, err := testClient.Do(req, nil)
+	jiraErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Expected *Error. Got %T: %v", err, err)
+	}
+	if jiraErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code %d. Got %d", http.StatusBadRequest, jiraErr.StatusCode)
+	}
+	if want := testServer.URL + "/rest/api/2/issue"; jiraErr.URL != want {
+		t.Errorf("Expected URL %q. Got %q", want, jiraErr.URL)
+	}
+	if len(jiraErr.ErrorMessages) != 1 || jiraErr.ErrorMessages[0] != "Issue could not be created" {
+		t.Errorf("Unexpected error messages: %v", jiraErr.ErrorMessages)
+	}
+	if want := "You must specify a summary of the issue."; jiraErr.Errors["summary"] != want {
+		t.Errorf("Expected field error %q. Got %q", want, jiraErr.Errors["summary"])
+	}
+	if msg := err.Error(); !strings.Contains(msg, "400") || !strings.Contains(msg, "summary: You must specify a summary of the issue.") {
+		t.Errorf("Unexpected error message: %s", msg)
+	}
+
+	// The body is still readable for the caller
+	data, _ := ioutil.ReadAll(resp.Body)
+	if string(data) != body {
+		t.Errorf("Expected body %s. Got %s", body, data)
+	}
+}
+
 // Test handling of an error caused by the internal http client's Do() function.
 // A redirect loop is pretty unlikely to occur within the Gerrit API, but does allow us to exercise the right code path.
 func TestClient_Do_RedirectLoop(t *testing.T) {
@@ -448,3 +1063,108 @@ func TestClient_Do_PagingInfoEmptyByDefault(t *testing.T) {
 		t.Errorf("StartAt not equal to 0")
 	}
 }
+
+func TestClient_Do_RateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "2018-03-01T10:15:30Z")
+		fmt.Fprint(w, `{}`)
+	})
+	testMux.HandleFunc("/unlimited", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := testClient.NewRequest("GET", "/limited", nil)
+	resp, err := testClient.Do(req, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.RateLimitLimit != 100 || resp.RateLimitRemaining != 42 {
+		t.Errorf("Expected limit 100 and remaining 42. Got %d and %d", resp.RateLimitLimit, resp.RateLimitRemaining)
+	}
+	if want := time.Date(2018, 3, 1, 10, 15, 30, 0, time.UTC); !resp.RateLimitReset.Equal(want) {
+		t.Errorf("Expected reset %s. Got %s", want, resp.RateLimitReset)
+	}
+
+	req, _ = testClient.NewRequest("GET", "/unlimited", nil)
+	resp, err = testClient.Do(req, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.RateLimitLimit != 0 || resp.RateLimitRemaining != 0 || !resp.RateLimitReset.IsZero() {
+		t.Errorf("Expected zero rate limit values. Got %+v", resp)
+	}
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var userAgents []string
+	testMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/myself", nil)
+	testClient.Do(req, nil)
+
+	c, err := NewClient(nil, testServer.URL, WithUserAgent("board-sync/2.1"))
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	c.Board.GetBoard(1)
+	c.User.Myself()
+	c.Webhook.GetByID("1")
+
+	want := []string{defaultUserAgent, "board-sync/2.1", "board-sync/2.1", "board-sync/2.1"}
+	if !reflect.DeepEqual(userAgents, want) {
+		t.Errorf("Expected User-Agents %v. Got %v", want, userAgents)
+	}
+}
+
+func TestClient_Do_WithTimeout_BodyReadable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/serverInfo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"7.0.0"}`)
+	})
+
+	req, _ := testClient.NewRequest("GET", "rest/api/2/serverInfo", nil, WithTimeout(time.Second))
+	ctx := req.Context()
+	resp, err := testClient.Do(req, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if req.Context() != ctx {
+		t.Error("Expected the context of the request to be left unchanged")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || string(body) != `{"version":"7.0.0"}` {
+		t.Errorf("Expected the body to be readable. Got %q, %v", body, err)
+	}
+	resp.Body.Close()
+}
+
+func TestETagCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newETagCache(2)
+	cache.set("a", &etagEntry{etag: "1"})
+	cache.set("b", &etagEntry{etag: "2"})
+	cache.get("a")
+	cache.set("c", &etagEntry{etag: "3"})
+
+	if cache.get("b") != nil {
+		t.Error("Expected the least recently used entry b to be evicted")
+	}
+	if cache.get("a") == nil || cache.get("c") == nil {
+		t.Error("Expected entries a and c to be cached")
+	}
+	if len(cache.entries) != 2 || cache.lru.Len() != 2 {
+		t.Errorf("Expected 2 cached entries. Got %d", len(cache.entries))
+	}
+}
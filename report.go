@@ -0,0 +1,301 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SprintReport represents the sprint report of a board, as shown in the JIRA Agile UI.
+type SprintReport struct {
+	Contents SprintReportContents `json:"contents" structs:"contents"`
+}
+
+// SprintReportContents lists the issues of a sprint report, grouped by their outcome
+type SprintReportContents struct {
+	CompletedIssues                   []SprintReportIssue `json:"completedIssues" structs:"completedIssues"`
+	IssuesNotCompletedInCurrentSprint []SprintReportIssue `json:"issuesNotCompletedInCurrentSprint" structs:"issuesNotCompletedInCurrentSprint"`
+	PuntedIssues                      []SprintReportIssue `json:"puntedIssues" structs:"puntedIssues"`
+	// IssueKeysAddedDuringSprint contains the keys of all issues that were added after the sprint was started
+	IssueKeysAddedDuringSprint map[string]bool `json:"issueKeysAddedDuringSprint" structs:"issueKeysAddedDuringSprint"`
+
+	// The *EstimateSum fields are the sums of the current estimates of the issues of each group,
+	// the *InitialEstimateSum fields the sums of their estimates at the start of the sprint.
+	CompletedIssuesEstimateSum           EstimateValue `json:"completedIssuesEstimateSum" structs:"completedIssuesEstimateSum"`
+	CompletedIssuesInitialEstimateSum    EstimateValue `json:"completedIssuesInitialEstimateSum" structs:"completedIssuesInitialEstimateSum"`
+	IssuesNotCompletedEstimateSum        EstimateValue `json:"issuesNotCompletedEstimateSum" structs:"issuesNotCompletedEstimateSum"`
+	IssuesNotCompletedInitialEstimateSum EstimateValue `json:"issuesNotCompletedInitialEstimateSum" structs:"issuesNotCompletedInitialEstimateSum"`
+	PuntedIssuesEstimateSum              EstimateValue `json:"puntedIssuesEstimateSum" structs:"puntedIssuesEstimateSum"`
+	PuntedIssuesInitialEstimateSum       EstimateValue `json:"puntedIssuesInitialEstimateSum" structs:"puntedIssuesInitialEstimateSum"`
+	AllIssuesEstimateSum                 EstimateValue `json:"allIssuesEstimateSum" structs:"allIssuesEstimateSum"`
+}
+
+// EstimateValue is a value of the board's estimation statistic, e.g. story points, in a report.
+// Value is nil if no issue has an estimate.
+type EstimateValue struct {
+	Value *float64 `json:"value,omitempty" structs:"value,omitempty"`
+	Text  string   `json:"text,omitempty" structs:"text,omitempty"`
+}
+
+// EstimateStatistic is the estimate of a single issue in a report
+type EstimateStatistic struct {
+	StatFieldID    string        `json:"statFieldId" structs:"statFieldId"`
+	StatFieldValue EstimateValue `json:"statFieldValue" structs:"statFieldValue"`
+}
+
+// SprintReportIssue represents a single issue of a sprint report
+type SprintReportIssue struct {
+	ID           int    `json:"id" structs:"id"`
+	Key          string `json:"key" structs:"key"`
+	Summary      string `json:"summary" structs:"summary"`
+	TypeName     string `json:"typeName" structs:"typeName"`
+	PriorityName string `json:"priorityName" structs:"priorityName"`
+	StatusID     string `json:"statusId" structs:"statusId"`
+	StatusName   string `json:"statusName" structs:"statusName"`
+	Assignee     string `json:"assignee" structs:"assignee"`
+	AssigneeName string `json:"assigneeName" structs:"assigneeName"`
+	Done         bool   `json:"done" structs:"done"`
+	Flagged      bool   `json:"flagged" structs:"flagged"`
+	// EstimateStatistic is the estimate at the start of the sprint, CurrentEstimateStatistic the estimate now.
+	// Both are nil if the board does not use estimation.
+	EstimateStatistic        *EstimateStatistic `json:"estimateStatistic,omitempty" structs:"estimateStatistic,omitempty"`
+	CurrentEstimateStatistic *EstimateStatistic `json:"currentEstimateStatistic,omitempty" structs:"currentEstimateStatistic,omitempty"`
+}
+
+// GetSprintReport returns the sprint report of a sprint, for a given board and sprint Id.
+// The agile REST API does not expose the sprint report, so the (private) greenhopper API is used.
+func (s *BoardService) GetSprintReport(boardID, sprintID int) (*SprintReport, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/greenhopper/1.0/rapid/charts/sprintreport?rapidViewId=%d&sprintId=%d", boardID, sprintID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := new(SprintReport)
+	resp, err := s.client.Do(req, report)
+	if err != nil {
+		return nil, resp, err
+	}
+	return report, resp, nil
+}
+
+// GetSprintScopeCreep returns the keys of all issues that were added to a sprint after it was started, sorted by key.
+// The agile REST API does not expose this information directly.
+// It is taken from the "issues added during sprint" section of the sprint report (see GetSprintReport).
+func (s *BoardService) GetSprintScopeCreep(boardID, sprintID int) ([]string, error) {
+	report, _, err := s.GetSprintReport(boardID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for key, added := range report.Contents.IssueKeysAddedDuringSprint {
+		if added {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Velocity represents the velocity chart of a board, as shown in the JIRA Agile UI.
+// Sprints lists the closed sprints of the chart, most recent first.
+// VelocityStatEntries holds the estimated and completed values of each sprint, keyed by sprint Id.
+type Velocity struct {
+	Sprints             []VelocitySprint          `json:"sprints" structs:"sprints"`
+	VelocityStatEntries map[int]VelocityStatEntry `json:"velocityStatEntries" structs:"velocityStatEntries"`
+}
+
+// VelocitySprint represents a single sprint of the velocity chart
+type VelocitySprint struct {
+	ID       int    `json:"id" structs:"id"`
+	Sequence int    `json:"sequence" structs:"sequence"`
+	Name     string `json:"name" structs:"name"`
+	State    string `json:"state" structs:"state"`
+	Goal     string `json:"goal" structs:"goal"`
+}
+
+// VelocityStatEntry holds the values of the board's estimation statistic, e.g. story points,
+// committed at the start of a sprint (Estimated) and completed by its end (Completed).
+type VelocityStatEntry struct {
+	Estimated VelocityStatValue `json:"estimated" structs:"estimated"`
+	Completed VelocityStatValue `json:"completed" structs:"completed"`
+}
+
+// VelocityStatValue is a single value of the velocity chart
+type VelocityStatValue struct {
+	Value float64 `json:"value" structs:"value"`
+	Text  string  `json:"text" structs:"text"`
+}
+
+// GetVelocityReport returns the velocity chart of a board, for a given board Id.
+// The agile REST API does not expose the velocity chart, so the (private) greenhopper API is used.
+// See GetVelocity to compute the velocity from the issues of the closed sprints instead.
+func (s *BoardService) GetVelocityReport(boardID int) (*Velocity, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/greenhopper/1.0/rapid/charts/velocity?rapidViewId=%d", boardID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	velocity := new(Velocity)
+	resp, err := s.client.Do(req, velocity)
+	if err != nil {
+		return nil, resp, err
+	}
+	return velocity, resp, nil
+}
+
+// SprintBurndown represents the scope change burndown chart of a sprint, as shown in the JIRA Agile UI.
+// Estimates are values of the board's estimation statistic, e.g. story points.
+type SprintBurndown struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	CompleteTime time.Time
+	// Changes lists every scope, estimate and status change of the sprint's issues, ordered by time
+	Changes []BurndownChange
+	// Remaining lists the work remaining in the sprint from its start on, after each change
+	Remaining []BurndownPoint
+}
+
+// BurndownChange represents a single change of an issue on the burndown chart.
+// Fields that were not changed are nil.
+type BurndownChange struct {
+	Time time.Time
+	Key  string
+	// Added is true if the issue was added to the sprint and false if it was removed from it
+	Added       *bool
+	OldEstimate *float64
+	NewEstimate *float64
+	// Done is true if the issue was moved to a done column and false if it was moved out of one
+	Done *bool
+}
+
+// BurndownPoint represents the work remaining in a sprint at a point in time
+type BurndownPoint struct {
+	Time      time.Time
+	Remaining float64
+}
+
+// burndownResult is only a small wrapper around the GetSprintBurndown method
+// to be able to parse the results
+type burndownResult struct {
+	Changes      map[string][]burndownChangeResult `json:"changes"`
+	StartTime    int64                             `json:"startTime"`
+	EndTime      int64                             `json:"endTime"`
+	CompleteTime int64                             `json:"completeTime"`
+}
+
+type burndownChangeResult struct {
+	Key   string `json:"key"`
+	Added *bool  `json:"added"`
+	StatC *struct {
+		OldValue *float64 `json:"oldValue"`
+		NewValue *float64 `json:"newValue"`
+	} `json:"statC"`
+	Column *struct {
+		NotDone bool `json:"notDone"`
+		Done    bool `json:"done"`
+	} `json:"column"`
+}
+
+// GetSprintBurndown returns the scope change burndown chart of a sprint, for a given board and sprint Id.
+// The agile REST API does not expose the burndown chart, so the (private) greenhopper API is used.
+// The remaining work is not part of the response; it is computed by replaying the changes of the sprint.
+func (s *BoardService) GetSprintBurndown(boardID, sprintID int) (*SprintBurndown, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/greenhopper/1.0/rapid/charts/scopechangeburndownchart?rapidViewId=%d&sprintId=%d", boardID, sprintID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(burndownResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	burndown := &SprintBurndown{
+		StartTime:    millisToTime(result.StartTime),
+		EndTime:      millisToTime(result.EndTime),
+		CompleteTime: millisToTime(result.CompleteTime),
+	}
+
+	var timestamps []int64
+	for raw := range result.Changes {
+		timestamp, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, resp, fmt.Errorf("Could not parse burndown change time %q: %s", raw, err)
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Sort(int64s(timestamps))
+
+	inSprint := make(map[string]bool)
+	done := make(map[string]bool)
+	estimates := make(map[string]float64)
+	remaining := func() float64 {
+		var sum float64
+		for key, estimate := range estimates {
+			if inSprint[key] && !done[key] {
+				sum += estimate
+			}
+		}
+		return sum
+	}
+
+	for _, timestamp := range timestamps {
+		if timestamp > result.StartTime && len(burndown.Remaining) == 0 {
+			burndown.Remaining = append(burndown.Remaining, BurndownPoint{Time: burndown.StartTime, Remaining: remaining()})
+		}
+
+		at := millisToTime(timestamp)
+		for _, c := range result.Changes[strconv.FormatInt(timestamp, 10)] {
+			change := BurndownChange{Time: at, Key: c.Key, Added: c.Added}
+			if c.Added != nil {
+				inSprint[c.Key] = *c.Added
+			}
+			if c.StatC != nil {
+				change.OldEstimate = c.StatC.OldValue
+				change.NewEstimate = c.StatC.NewValue
+				estimates[c.Key] = 0
+				if c.StatC.NewValue != nil {
+					estimates[c.Key] = *c.StatC.NewValue
+				}
+			}
+			if c.Column != nil {
+				isDone := c.Column.Done && !c.Column.NotDone
+				change.Done = &isDone
+				done[c.Key] = isDone
+			}
+			burndown.Changes = append(burndown.Changes, change)
+		}
+
+		if timestamp > result.StartTime {
+			burndown.Remaining = append(burndown.Remaining, BurndownPoint{Time: at, Remaining: remaining()})
+		}
+	}
+	if len(burndown.Remaining) == 0 && result.StartTime != 0 {
+		burndown.Remaining = append(burndown.Remaining, BurndownPoint{Time: burndown.StartTime, Remaining: remaining()})
+	}
+
+	return burndown, resp, nil
+}
+
+// millisToTime converts milliseconds since the epoch, as used by the greenhopper API, to a time.
+// 0 is converted to the zero time.
+func millisToTime(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
+
+// int64s attaches the methods of sort.Interface to []int64, sorting in increasing order
+type int64s []int64
+
+func (p int64s) Len() int           { return len(p) }
+func (p int64s) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p int64s) Less(i, j int) bool { return p[i] < p[j] }
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestBoardService_GetSprintReport(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapid/charts/sprintreport"
+
+	raw, err := ioutil.ReadFile("./mocks/sprint_report.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?rapidViewId=1&sprintId=12")
+		fmt.Fprint(w, string(raw))
+	})
+
+	report, _, err := testClient.Board.GetSprintReport(1, 12)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if report == nil {
+		t.Fatal("Expected sprint report. Sprint report is nil")
+	}
+	if len(report.Contents.CompletedIssues) != 2 {
+		t.Errorf("Expected 2 completed issues. Got %d", len(report.Contents.CompletedIssues))
+	}
+	if len(report.Contents.IssuesNotCompletedInCurrentSprint) != 1 {
+		t.Errorf("Expected 1 not completed issue. Got %d", len(report.Contents.IssuesNotCompletedInCurrentSprint))
+	}
+	if len(report.Contents.PuntedIssues) != 1 || report.Contents.PuntedIssues[0].Key != "AR-4" {
+		t.Errorf("Expected punted issue AR-4. Got %+v", report.Contents.PuntedIssues)
+	}
+}
+
+func TestBoardService_GetSprintReport_Estimates(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapid/charts/sprintreport"
+
+	raw, err := ioutil.ReadFile("./mocks/sprint_report.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, string(raw))
+	})
+
+	report, _, err := testClient.Board.GetSprintReport(1, 12)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	contents := report.Contents
+	if sum := contents.CompletedIssuesEstimateSum; sum.Value == nil || *sum.Value != 7 || sum.Text != "7.0" {
+		t.Errorf("Expected completed estimate sum 7. Got %+v", sum)
+	}
+	if sum := contents.CompletedIssuesInitialEstimateSum.Value; sum == nil || *sum != 5 {
+		t.Errorf("Expected completed initial estimate sum 5. Got %v", sum)
+	}
+	if sum := contents.IssuesNotCompletedInitialEstimateSum; sum.Value != nil || sum.Text != "null" {
+		t.Errorf("Expected no not completed initial estimate sum. Got %+v", sum)
+	}
+	if sum := contents.PuntedIssuesEstimateSum.Value; sum == nil || *sum != 1 {
+		t.Errorf("Expected punted estimate sum 1. Got %v", sum)
+	}
+
+	issue := contents.CompletedIssues[0]
+	if issue.EstimateStatistic == nil || issue.CurrentEstimateStatistic == nil {
+		t.Fatalf("Expected estimate statistics for %s. Got %+v", issue.Key, issue)
+	}
+	if stat := issue.EstimateStatistic; stat.StatFieldID != "customfield_10002" || *stat.StatFieldValue.Value != 3 {
+		t.Errorf("Expected initial estimate 3. Got %+v", stat)
+	}
+	if value := issue.CurrentEstimateStatistic.StatFieldValue.Value; *value != 5 {
+		t.Errorf("Expected current estimate 5. Got %v", *value)
+	}
+}
+
+func TestBoardService_GetVelocityReport(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapid/charts/velocity"
+
+	raw, err := ioutil.ReadFile("./mocks/velocity.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?rapidViewId=1")
+		fmt.Fprint(w, string(raw))
+	})
+
+	velocity, _, err := testClient.Board.GetVelocityReport(1)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(velocity.Sprints) != 2 || velocity.Sprints[0].ID != 12 || velocity.Sprints[0].Goal != "Log in" {
+		t.Errorf("Unexpected sprints: %+v", velocity.Sprints)
+	}
+	entry, ok := velocity.VelocityStatEntries[11]
+	if !ok {
+		t.Fatalf("Expected an entry for sprint 11. Got %+v", velocity.VelocityStatEntries)
+	}
+	if entry.Estimated.Value != 21 || entry.Completed.Value != 13 {
+		t.Errorf("Expected 21 estimated and 13 completed points. Got %+v", entry)
+	}
+}
+
+func TestBoardService_GetSprintScopeCreep(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapid/charts/sprintreport"
+
+	raw, err := ioutil.ReadFile("./mocks/sprint_report.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?rapidViewId=1&sprintId=12")
+		fmt.Fprint(w, string(raw))
+	})
+
+	keys, err := testClient.Board.GetSprintScopeCreep(1, 12)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if want := []string{"AR-2", "AR-3"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected added issues %v. Got %v", want, keys)
+	}
+}
+
+func TestBoardService_GetSprintBurndown(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/greenhopper/1.0/rapid/charts/scopechangeburndownchart"
+
+	raw, err := ioutil.ReadFile("./mocks/sprint_burndown.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?rapidViewId=1&sprintId=2")
+		fmt.Fprint(w, string(raw))
+	})
+
+	burndown, _, err := testClient.Board.GetSprintBurndown(1, 2)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if burndown == nil {
+		t.Fatal("Expected burndown. Burndown is nil")
+	}
+	if !burndown.CompleteTime.IsZero() {
+		t.Errorf("Expected no complete time. Got %s", burndown.CompleteTime)
+	}
+	if len(burndown.Changes) != 8 {
+		t.Fatalf("Expected 8 changes. Got %d", len(burndown.Changes))
+	}
+	if done := burndown.Changes[6]; done.Key != "TEST-1" || done.Done == nil || !*done.Done {
+		t.Errorf("Expected TEST-1 to be done. Got %+v", done)
+	}
+
+	var remaining []float64
+	for _, point := range burndown.Remaining {
+		remaining = append(remaining, point.Remaining)
+	}
+	if want := []float64{8, 10, 5, 10}; !reflect.DeepEqual(remaining, want) {
+		t.Errorf("Expected remaining work %v. Got %v", want, remaining)
+	}
+	if !burndown.Remaining[0].Time.Equal(burndown.StartTime) {
+		t.Errorf("Expected first point at sprint start %s. Got %s", burndown.StartTime, burndown.Remaining[0].Time)
+	}
+}
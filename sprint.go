@@ -1,7 +1,9 @@
 package jira
 
 import (
+	"context"
 	"fmt"
+	"time"
 )
 
 // SprintService handles sprints in JIRA Agile API.
@@ -15,6 +17,31 @@ type IssuesWrapper struct {
 	Issues []string `json:"issues"`
 }
 
+// sprintPayload is the request payload to create or update a sprint.
+// Unlike Sprint, it only contains the fields JIRA accepts and omits all unset fields.
+type sprintPayload struct {
+	Name          string     `json:"name,omitempty"`
+	OriginBoardID int        `json:"originBoardId,omitempty"`
+	State         string     `json:"state,omitempty"`
+	StartDate     *time.Time `json:"startDate,omitempty"`
+	EndDate       *time.Time `json:"endDate,omitempty"`
+	CompleteDate  *time.Time `json:"completeDate,omitempty"`
+	Goal          string     `json:"goal,omitempty"`
+}
+
+// newSprintPayload returns the payload to create or update sprint
+func newSprintPayload(sprint *Sprint) sprintPayload {
+	return sprintPayload{
+		Name:          sprint.Name,
+		OriginBoardID: sprint.OriginBoardID,
+		State:         sprint.State,
+		StartDate:     nonZeroTime(sprint.StartDate),
+		EndDate:       nonZeroTime(sprint.EndDate),
+		CompleteDate:  nonZeroTime(sprint.CompleteDate),
+		Goal:          sprint.Goal,
+	}
+}
+
 // IssuesInSprintResult represents a wrapper struct for search result
 type IssuesInSprintResult struct {
 	Issues []Issue `json:"issues"`
@@ -23,9 +50,13 @@ type IssuesInSprintResult struct {
 // MoveIssuesToSprint moves issues to a sprint, for a given sprint Id.
 // Issues can only be moved to open or active sprints.
 // The maximum number of issues that can be moved in one operation is 50.
+// An error is returned without sending a request if no issues are given.
 //
 // JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-moveIssuesToSprint
 func (s *SprintService) MoveIssuesToSprint(sprintID int, issueIDs []string) (*Response, error) {
+	if len(issueIDs) == 0 {
+		return nil, fmt.Errorf("No issues given to move to sprint %d", sprintID)
+	}
 	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d/issue", sprintID)
 
 	payload := IssuesWrapper{Issues: issueIDs}
@@ -40,21 +71,178 @@ func (s *SprintService) MoveIssuesToSprint(sprintID int, issueIDs []string) (*Re
 	return resp, err
 }
 
+// MoveIssuesToBacklog moves issues to the backlog, i.e. removes them from all future and active sprints.
+// The maximum number of issues that can be moved in one operation is 50.
+// An error is returned without sending a request if no issues are given.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/backlog-moveIssuesToBacklog
+func (s *SprintService) MoveIssuesToBacklog(issueIDs []string) (*Response, error) {
+	if len(issueIDs) == 0 {
+		return nil, fmt.Errorf("No issues given to move to the backlog")
+	}
+	apiEndpoint := "rest/agile/1.0/backlog/issue"
+
+	payload := IssuesWrapper{Issues: issueIDs}
+
+	req, err := s.client.NewRequest("POST", apiEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
 // GetIssuesForSprint returns all issues in a sprint, for a given sprint Id.
 // This only includes issues that the user has permission to view.
-// By default, the returned issues are ordered by rank.
+// The issues are requested with IssueListOptions.OrderByRank and fetched page by page,
+// so they are returned in rank order, i.e. in the order of the board, across all pages.
+// The returned Response is the one of the last page. Use GetIssuesForSprintWithOptions to page manually.
 //
 //  JIRA API Docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-getIssuesForSprint
 func (s *SprintService) GetIssuesForSprint(sprintID int) ([]Issue, *Response, error) {
-	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d/issue?maxResults=1000", sprintID)
+	var resp *Response
+	issues, err := getAllIssues(&IssueListOptions{OrderByRank: true}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		var page []Issue
+		var err error
+		page, resp, err = s.GetIssuesForSprintWithOptions(sprintID, opt)
+		return page, resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return issues, resp, nil
+}
+
+// GetIssuesForSprintWithOptions returns a page of issues in a sprint, for a given sprint Id.
+// Use options.Expand = "changelog" to include the change history of every issue, e.g. for time-in-status analysis.
+// Request the "parent" and "subtasks" fields to build the sub-task hierarchy, see GroupBySubtaskParent.
+// Since changelogs make the response considerably larger, JIRA may return less issues per page than requested.
+// The paging info of the returned Response reflects the page size actually used.
+//
+//  JIRA API Docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-getIssuesForSprint
+func (s *SprintService) GetIssuesForSprintWithOptions(sprintID int, options *IssueListOptions) ([]Issue, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d/issue", sprintID)
+	url, err := s.client.issueListURL(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(searchResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Issues, resp, nil
+}
+
+// Get returns the sprint for a given sprint Id.
+// The sprint will only be returned if the user can view the board that the sprint was created on,
+// or view at least one of the issues in the sprint.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-getSprint
+func (s *SprintService) Get(sprintID int) (*Sprint, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
 
 	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sprint := new(Sprint)
+	resp, err := s.client.Do(req, sprint)
+	if err != nil {
+		return nil, resp, err
+	}
+	return sprint, resp, nil
+}
+
+// GetBoardForSprint returns the board a sprint was created on, for a given sprint Id.
+// This is a convenience method for when only the sprint Id is known, e.g. from a webhook.
+// An error is returned if the sprint has no origin board.
+func (s *SprintService) GetBoardForSprint(sprintID int) (*Board, *Response, error) {
+	sprint, resp, err := s.Get(sprintID)
+	if err != nil {
+		return nil, resp, err
+	}
+	if sprint.OriginBoardID == 0 {
+		return nil, resp, fmt.Errorf("Sprint %d has no origin board", sprintID)
+	}
+
+	return s.client.Board.GetBoard(sprint.OriginBoardID)
+}
+
+// getIssueCount returns the number of issues in a sprint, for a given sprint Id.
+// Only the total is requested (maxResults=0), the issues themselves are not transferred.
+func (s *SprintService) getIssueCount(ctx context.Context, sprintID int) (int, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d/issue?maxResults=0", sprintID)
+
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	result := new(searchResult)
+	resp, err := s.client.Do(req.WithContext(ctx), result)
+	if err != nil {
+		return 0, resp, err
+	}
+	return result.Total, resp, nil
+}
 
+// Create creates a future sprint on the board sprint.OriginBoardID.
+// Name and OriginBoardID are required; StartDate, EndDate and Goal are optional.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-createSprint
+func (s *SprintService) Create(sprint *Sprint) (*Sprint, *Response, error) {
+	payload := newSprintPayload(sprint)
+	payload.State = ""
+	payload.CompleteDate = nil
+
+	req, err := s.client.NewRequest("POST", "rest/agile/1.0/sprint", payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(Sprint)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result, resp, nil
+}
+
+// Update updates a sprint, for a given sprint Id. Only the fields set in sprint are changed.
+// Setting State to "active" starts the sprint, setting it to "closed" completes it.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-partiallyUpdateSprint
+func (s *SprintService) Update(sprintID int, sprint *Sprint) (*Sprint, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	req, err := s.client.NewRequest("POST", apiEndpoint, newSprintPayload(sprint))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	result := new(IssuesInSprintResult)
+	result := new(Sprint)
 	resp, err := s.client.Do(req, result)
-	return result.Issues, resp, err
+	if err != nil {
+		return nil, resp, err
+	}
+	return result, resp, nil
+}
+
+// Delete deletes a sprint, for a given sprint Id. Only future sprints can be deleted.
+// Issues of the deleted sprint are moved to the backlog.
+//
+// JIRA API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-deleteSprint
+func (s *SprintService) Delete(sprintID int) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	req, err := s.client.NewRequest("DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
 }
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSprintService_MoveIssuesToSprint(t *testing.T) {
@@ -38,6 +40,47 @@ func TestSprintService_MoveIssuesToSprint(t *testing.T) {
 	}
 }
 
+func TestSprintService_MoveIssuesToSprint_NoIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/123/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request")
+	})
+
+	if _, err := testClient.Sprint.MoveIssuesToSprint(123, nil); err == nil {
+		t.Error("Expected an error. Got none")
+	}
+}
+
+func TestSprintService_MoveIssuesToBacklog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/backlog/issue"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload IssuesWrapper
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if want := []string{"KEY-1", "KEY-2"}; !reflect.DeepEqual(payload.Issues, want) {
+			t.Errorf("Expected issues %v in payload. Got %v", want, payload.Issues)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Sprint.MoveIssuesToBacklog([]string{"KEY-1", "KEY-2"}); err != nil {
+		t.Errorf("Got error: %v", err)
+	}
+	if _, err := testClient.Sprint.MoveIssuesToBacklog([]string{}); err == nil {
+		t.Error("Expected an error for no issues. Got none")
+	}
+}
+
 func TestSprintService_GetIssuesForSprint(t *testing.T) {
 	setup()
 	defer teardown()
@@ -65,3 +108,263 @@ func TestSprintService_GetIssuesForSprint(t *testing.T) {
 	}
 
 }
+
+func TestSprintService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/740"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"id":740,"self":"https://jira.com/rest/agile/1.0/sprint/740","state":"closed","name":"Iteration-10",
+			"startDate":"2016-04-11T07:29:03.294-07:00","endDate":"2016-04-27T08:29:00.000-07:00","originBoardId":734}`)
+	})
+
+	sprint, _, err := testClient.Sprint.Get(740)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if sprint == nil {
+		t.Fatal("Expected sprint. Sprint is nil")
+	}
+	if sprint.Name != "Iteration-10" || sprint.OriginBoardID != 734 || sprint.StartDate == nil {
+		t.Errorf("Unexpected sprint: %+v", sprint)
+	}
+}
+
+func TestSprintService_GetBoardForSprint(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/740", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":740,"state":"closed","name":"Iteration-10","originBoardId":734}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/734", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":734,"name":"Test Weekly","type":"scrum"}`)
+	})
+
+	board, _, err := testClient.Sprint.GetBoardForSprint(740)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if board == nil || board.ID != 734 {
+		t.Errorf("Expected board 734. Got %+v", board)
+	}
+}
+
+func TestSprintService_GetBoardForSprint_NoOriginBoard(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/740", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":740,"state":"closed","name":"Iteration-10"}`)
+	})
+
+	board, _, err := testClient.Sprint.GetBoardForSprint(740)
+	if err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if board != nil {
+		t.Errorf("Expected no board. Got %+v", board)
+	}
+}
+
+func TestSprintService_GetIssuesForSprintWithOptions_Changelog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint/123/issue"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if expand := r.URL.Query().Get("expand"); expand != "changelog" {
+			t.Errorf("Expected expand=changelog. Got %q", expand)
+		}
+		startAt, key := "0", "TEST-1"
+		if r.URL.Query().Get("startAt") == "1" {
+			startAt, key = "1", "TEST-2"
+		}
+		fmt.Fprintf(w, `{"startAt":%s,"maxResults":1,"total":2,"issues":[{"key":"%s","changelog":{"histories":[
+			{"id":"10000","created":"2017-04-04T17:08:54.963+0000","items":[
+				{"field":"status","fieldtype":"jira","from":"1","fromString":"To Do","to":"3","toString":"In Progress"}]}]}}]}`, startAt, key)
+	})
+
+	issues, err := getAllIssues(&IssueListOptions{SearchOptions: SearchOptions{MaxResults: 50, Expand: "changelog"}}, func(opt *IssueListOptions) ([]Issue, *Response, error) {
+		return testClient.Sprint.GetIssuesForSprintWithOptions(123, opt)
+	})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues. Got %d", len(issues))
+	}
+	for _, issue := range issues {
+		if issue.Changelog == nil || len(issue.Changelog.Histories) != 1 {
+			t.Fatalf("Expected 1 changelog history for %s. Got %+v", issue.Key, issue.Changelog)
+		}
+		if item := issue.Changelog.Histories[0].Items[0]; item.Field != "status" || item.ToString != "In Progress" {
+			t.Errorf("Unexpected changelog item for %s: %+v", issue.Key, item)
+		}
+	}
+}
+
+func TestSprintService_GetIssuesForSprint_OrderByRank(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint/123/issue"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql := r.URL.Query().Get("jql"); jql != "ORDER BY Rank ASC" {
+			t.Errorf("Unexpected JQL: %q", jql)
+		}
+		if r.URL.Query().Get("startAt") == "2" {
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"issues":[{"key":"TEST-1"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"issues":[{"key":"TEST-7"},{"key":"TEST-3"}]}`)
+	})
+
+	issues, _, err := testClient.Sprint.GetIssuesForSprint(123)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	if want := []string{"TEST-7", "TEST-3", "TEST-1"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected issues in rank order %v. Got %v", want, keys)
+	}
+}
+
+func TestSprintService_GetIssuesForSprintWithOptions_OrderByRank_KeepsOrderBy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/123/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql := r.URL.Query().Get("jql"); jql != `summary ~ "order by" ORDER BY created` {
+			t.Errorf("Unexpected JQL: %q", jql)
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":0,"issues":[]}`)
+	})
+
+	opt := &IssueListOptions{JQL: `summary ~ "order by" ORDER BY created`, OrderByRank: true}
+	if _, _, err := testClient.Sprint.GetIssuesForSprintWithOptions(123, opt); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestSprintService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		want := map[string]interface{}{
+			"name":          "Sprint 1",
+			"originBoardId": float64(5),
+			"startDate":     "2017-04-11T15:22:00Z",
+			"endDate":       "2017-04-25T15:22:00Z",
+			"goal":          "Ship it",
+		}
+		if !reflect.DeepEqual(payload, want) {
+			t.Errorf("Expected payload %v. Got %v", want, payload)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":37,"self":"http://www.example.com/jira/rest/agile/1.0/sprint/37","state":"future","name":"Sprint 1",
+			"startDate":"2017-04-11T15:22:00.000Z","endDate":"2017-04-25T15:22:00.000Z","originBoardId":5,"goal":"Ship it"}`)
+	})
+
+	start := time.Date(2017, 4, 11, 15, 22, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	sprint, _, err := testClient.Sprint.Create(&Sprint{Name: "Sprint 1", OriginBoardID: 5, StartDate: &start, EndDate: &end, Goal: "Ship it"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if sprint == nil || sprint.ID != 37 || sprint.State != "future" || sprint.Goal != "Ship it" {
+		t.Errorf("Unexpected sprint: %+v", sprint)
+	}
+}
+
+func TestSprintService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"state":"active"}`+"\n"; got != want {
+			t.Errorf("Expected body %q. Got %q", want, got)
+		}
+		fmt.Fprint(w, `{"id":37,"state":"active","name":"Sprint 1","originBoardId":5}`)
+	})
+
+	sprint, _, err := testClient.Sprint.Update(37, &Sprint{State: "active"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if sprint == nil || sprint.State != "active" {
+		t.Errorf("Expected active sprint. Got %+v", sprint)
+	}
+}
+
+func TestSprintService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, testAPIEndpoint)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Sprint.Delete(37); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestSprintService_GetIssuesForSprintWithOptions_Subtasks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testAPIEndpoint := "/rest/agile/1.0/sprint/123/issue"
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?fields=summary%2Cparent%2Csubtasks")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":3,"issues":[
+			{"key":"TEST-1","fields":{"summary":"Parent","subtasks":[{"id":"10002","key":"TEST-2"},{"id":"10003","key":"TEST-3"}]}},
+			{"key":"TEST-2","fields":{"summary":"First","parent":{"id":"10001","key":"TEST-1"}}},
+			{"key":"TEST-3","fields":{"summary":"Second","parent":{"id":"10001","key":"TEST-1"}}}]}`)
+	})
+
+	issues, _, err := testClient.Sprint.GetIssuesForSprintWithOptions(123, &IssueListOptions{Fields: []string{"summary", "parent", "subtasks"}})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(issues) != 3 || len(issues[0].Fields.Subtasks) != 2 {
+		t.Fatalf("Expected 3 issues, the first one with 2 sub-tasks. Got %+v", issues)
+	}
+
+	groups := GroupBySubtaskParent(issues)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 parent. Got %d", len(groups))
+	}
+	subtasks := groups["TEST-1"]
+	if len(subtasks) != 2 || subtasks[0].Key != "TEST-2" || subtasks[1].Key != "TEST-3" {
+		t.Errorf("Expected sub-tasks TEST-2 and TEST-3 of TEST-1. Got %+v", subtasks)
+	}
+}
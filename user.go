@@ -1,13 +1,29 @@
 package jira
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
+// UserNotFoundError is returned by UserService.Delete if the user does not exist (anymore).
+// Err is the error JIRA responded with.
+type UserNotFoundError struct {
+	Username string
+	Err      *Error
+}
+
+// Error returns the username and the error JIRA responded with.
+func (e *UserNotFoundError) Error() string {
+	return fmt.Sprintf("User %q not found: %s", e.Username, e.Err)
+}
+
 // UserService handles users for the JIRA instance / API.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user
@@ -18,6 +34,7 @@ type UserService struct {
 // User represents a JIRA user.
 type User struct {
 	Self            string     `json:"self,omitempty" structs:"self,omitempty"`
+	AccountID       string     `json:"accountId,omitempty" structs:"accountId,omitempty"`
 	Name            string     `json:"name,omitempty" structs:"name,omitempty"`
 	Password        string     `json:"-"`
 	Key             string     `json:"key,omitempty" structs:"key,omitempty"`
@@ -27,6 +44,78 @@ type User struct {
 	Active          bool       `json:"active,omitempty" structs:"active,omitempty"`
 	TimeZone        string     `json:"timeZone,omitempty" structs:"timeZone,omitempty"`
 	ApplicationKeys []string   `json:"applicationKeys,omitempty" structs:"applicationKeys,omitempty"`
+	// Locale is the language setting of the user in Java notation, e.g. "en_US", see Lang
+	Locale string `json:"locale,omitempty" structs:"locale,omitempty"`
+	// Groups and ApplicationRoles are only returned if they are expanded
+	Groups           *UserGroups       `json:"groups,omitempty" structs:"groups,omitempty"`
+	ApplicationRoles *ApplicationRoles `json:"applicationRoles,omitempty" structs:"applicationRoles,omitempty"`
+}
+
+// Lang returns the locale of the user as a BCP 47 language tag, e.g. "en-US" for the locale "en_US".
+// An empty string is returned if the locale is unknown.
+func (u *User) Lang() string {
+	parts := strings.Split(strings.Replace(u.Locale, "_", "-", -1), "-")
+	if parts[0] == "" {
+		return ""
+	}
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) > 1 && len(parts[1]) == 2 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+	return strings.Join(parts, "-")
+}
+
+// UserGroups is a wrapper for the groups a user belongs to
+type UserGroups struct {
+	Size  int         `json:"size,omitempty" structs:"size,omitempty"`
+	Items []UserGroup `json:"items,omitempty" structs:"items,omitempty"`
+}
+
+// UserGroup represents a single group a user belongs to
+type UserGroup struct {
+	Name string `json:"name,omitempty" structs:"name,omitempty"`
+	Self string `json:"self,omitempty" structs:"self,omitempty"`
+}
+
+// ApplicationRoles is a wrapper for the application roles of a user
+type ApplicationRoles struct {
+	Size  int               `json:"size,omitempty" structs:"size,omitempty"`
+	Items []ApplicationRole `json:"items,omitempty" structs:"items,omitempty"`
+}
+
+// ApplicationRole represents a single application role (e.g. "jira-software") of a user
+type ApplicationRole struct {
+	Key  string `json:"key,omitempty" structs:"key,omitempty"`
+	Name string `json:"name,omitempty" structs:"name,omitempty"`
+}
+
+// PickerOptions specifies the optional parameters to UserService.Picker
+type PickerOptions struct {
+	// MaxResults is the maximum number of users to return. Default: 50.
+	MaxResults int `url:"maxResults,omitempty"`
+	// ShowAvatar includes the avatar URL of each user.
+	ShowAvatar bool `url:"showAvatar,omitempty"`
+	// Exclude is a list of user names to exclude from the result.
+	Exclude []string `url:"exclude,omitempty"`
+}
+
+// PickerUser represents a user as returned by the user picker.
+// HTML contains the display name and email address with the matching parts highlighted (<strong>).
+type PickerUser struct {
+	AccountID   string `json:"accountId,omitempty" structs:"accountId,omitempty"`
+	Name        string `json:"name,omitempty" structs:"name,omitempty"`
+	Key         string `json:"key,omitempty" structs:"key,omitempty"`
+	HTML        string `json:"html,omitempty" structs:"html,omitempty"`
+	DisplayName string `json:"displayName,omitempty" structs:"displayName,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty" structs:"avatarUrl,omitempty"`
+}
+
+// pickerResult is only a small wrapper around the Picker method
+// to be able to parse the results
+type pickerResult struct {
+	Users  []PickerUser `json:"users"`
+	Total  int          `json:"total"`
+	Header string       `json:"header"`
 }
 
 type UserPermissionSearch struct {
@@ -38,11 +127,47 @@ type UserPermissionSearch struct {
 	MaxResults  int    `json:"maxResults,omitempty"`
 }
 
-// Get gets user info from JIRA
+// Get gets user info from JIRA, for a given username.
+// Usernames are deprecated on JIRA Cloud, use GetByAccountID there. JIRA Server still identifies users by username.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-getUser
 func (s *UserService) Get(username string) (*User, *Response, error) {
-	apiEndpoint := fmt.Sprintf("/rest/api/2/user?username=%s", username)
+	return s.get("username", username)
+}
+
+// GetByAccountID gets user info from JIRA, for a given account Id.
+// Account Ids are required to identify users on JIRA Cloud.
+//
+// JIRA API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/#api-rest-api-2-user-get
+func (s *UserService) GetByAccountID(accountID string) (*User, *Response, error) {
+	return s.get("accountId", accountID)
+}
+
+// GetGroups returns the names of the groups the user with the given account Id belongs to.
+//
+// JIRA API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/#api-rest-api-2-user-get
+func (s *UserService) GetGroups(accountID string) ([]string, *Response, error) {
+	user, resp, err := s.get("accountId", accountID, "groups")
+	if err != nil {
+		return nil, resp, err
+	}
+	if user.Groups == nil {
+		return []string{}, resp, nil
+	}
+
+	groups := make([]string, len(user.Groups.Items))
+	for i, group := range user.Groups.Items {
+		groups[i] = group.Name
+	}
+	return groups, resp, nil
+}
+
+// get gets user info from JIRA, for a user identified by the query parameter param, and expands the given sections.
+func (s *UserService) get(param, value string, expand ...string) (*User, *Response, error) {
+	apiEndpoint := fmt.Sprintf("/rest/api/2/user?%s=%s", param, url.QueryEscape(value))
+	if len(expand) > 0 {
+		apiEndpoint += "&expand=" + url.QueryEscape(strings.Join(expand, ","))
+	}
 	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
 	if err != nil {
 		return nil, nil, err
@@ -60,7 +185,19 @@ func (s *UserService) Get(username string) (*User, *Response, error) {
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/myself-getUser
 func (s *UserService) Myself() (*User, *Response, error) {
-	req, err := s.client.NewRequest("GET", "/rest/api/2/myself", nil)
+	return s.MyselfWithExpand()
+}
+
+// MyselfWithExpand gets the current user from JIRA and expands the given sections.
+// Use "groups" and "applicationRoles" to populate User.Groups and User.ApplicationRoles.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/myself-getUser
+func (s *UserService) MyselfWithExpand(expand ...string) (*User, *Response, error) {
+	apiEndpoint := "/rest/api/2/myself"
+	if len(expand) > 0 {
+		apiEndpoint += "?expand=" + url.QueryEscape(strings.Join(expand, ","))
+	}
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -73,6 +210,52 @@ func (s *UserService) Myself() (*User, *Response, error) {
 	return user, resp, nil
 }
 
+// Permission represents a single permission and whether the current user has it
+type Permission struct {
+	ID             string `json:"id,omitempty" structs:"id,omitempty"`
+	Key            string `json:"key,omitempty" structs:"key,omitempty"`
+	Name           string `json:"name,omitempty" structs:"name,omitempty"`
+	Type           string `json:"type,omitempty" structs:"type,omitempty"`
+	Description    string `json:"description,omitempty" structs:"description,omitempty"`
+	HavePermission bool   `json:"havePermission" structs:"havePermission"`
+}
+
+// permissionsResult is only a small wrapper around the GetMyPermissions method
+// to be able to parse the results
+type permissionsResult struct {
+	Permissions map[string]Permission `json:"permissions"`
+}
+
+// GetMyPermissions returns the permissions of the current user, keyed by permission key.
+// If projectKey is empty, global permissions are evaluated, otherwise permissions in the given project.
+// If permissions are given, only these are returned (e.g. "BROWSE_PROJECTS"), otherwise all permissions are.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/mypermissions-getPermissions
+func (s *UserService) GetMyPermissions(projectKey string, permissions ...string) (map[string]Permission, *Response, error) {
+	params := url.Values{}
+	if projectKey != "" {
+		params.Set("projectKey", projectKey)
+	}
+	if len(permissions) > 0 {
+		params.Set("permissions", strings.Join(permissions, ","))
+	}
+	apiEndpoint := "/rest/api/2/mypermissions"
+	if len(params) > 0 {
+		apiEndpoint += "?" + params.Encode()
+	}
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(permissionsResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Permissions, resp, nil
+}
+
 // Create creates an user in JIRA.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-createUser
@@ -101,6 +284,135 @@ func (s *UserService) Create(user *User) (*User, *Response, error) {
 	return responseUser, resp, nil
 }
 
+// Delete deletes a user in JIRA, for a given username.
+// If the user does not exist, a *UserNotFoundError is returned, so deleting a user that is already gone can be told apart
+// from a failed deletion.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-removeUser
+func (s *UserService) Delete(username string) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("/rest/api/2/user?username=%s", url.QueryEscape(username))
+	req, err := s.client.NewRequest("DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if jiraErr, ok := err.(*Error); ok && jiraErr.StatusCode == http.StatusNotFound {
+		return resp, &UserNotFoundError{Username: username, Err: jiraErr}
+	}
+	return resp, err
+}
+
+// UserUpdate holds the changes to a user for UserService.Update.
+// Only fields that are set (non-nil) are sent, all others are left unchanged.
+type UserUpdate struct {
+	Name         *string `json:"name,omitempty"`
+	EmailAddress *string `json:"emailAddress,omitempty"`
+	DisplayName  *string `json:"displayName,omitempty"`
+	// Active deactivates a user if it points to false
+	Active *bool `json:"active,omitempty"`
+}
+
+// Update updates a user in JIRA, for a given account Id, and returns the updated user.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-updateUser
+func (s *UserService) Update(accountID string, update *UserUpdate) (*User, *Response, error) {
+	apiEndpoint := fmt.Sprintf("/rest/api/2/user?accountId=%s", url.QueryEscape(accountID))
+	req, err := s.client.NewRequest("PUT", apiEndpoint, update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	responseUser := new(User)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp, fmt.Errorf("Could not read the returned data")
+	}
+	err = json.Unmarshal(data, responseUser)
+	if err != nil {
+		return nil, resp, fmt.Errorf("Could not unmarshall the data into struct")
+	}
+	return responseUser, resp, nil
+}
+
+// avatarFilename returns the file name of an uploaded avatar with the given media type, e.g. "avatar.png" for "image/png".
+func avatarFilename(contentType string) string {
+	subtype := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	if i := strings.LastIndex(subtype, "/"); i >= 0 {
+		subtype = subtype[i+1:]
+	}
+	if subtype == "" {
+		return "avatar"
+	}
+	return "avatar." + subtype
+}
+
+// avatarCropping describes the part of a temporary avatar that becomes the avatar.
+// It is returned by the upload of a temporary avatar and sent back to confirm the crop.
+type avatarCropping struct {
+	CropperWidth   int    `json:"cropperWidth"`
+	CropperOffsetX int    `json:"cropperOffsetX"`
+	CropperOffsetY int    `json:"cropperOffsetY"`
+	URL            string `json:"url,omitempty"`
+	NeedsCropping  bool   `json:"needsCropping"`
+}
+
+// userAvatar is the avatar created from a temporary avatar
+type userAvatar struct {
+	ID string `json:"id"`
+}
+
+// SetAvatar uploads the image read from r as avatar of the user with the given account Id and returns the Id of the created avatar.
+// contentType is the media type of the image, e.g. "image/png".
+// The image is uploaded as temporary avatar first, which is then converted into an avatar using the crop suggested by JIRA.
+// JIRA requires the file name and size of the upload; the image is read completely to determine its size
+// and its file name is derived from contentType, e.g. "avatar.png".
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/server/#api/2/user-storeTemporaryAvatar
+// and https://docs.atlassian.com/jira/REST/server/#api/2/user-createAvatarFromTemporary
+func (s *UserService) SetAvatar(accountID string, r io.Reader, contentType string) (string, *Response, error) {
+	image, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := "?accountId=" + url.QueryEscape(accountID)
+	upload := url.Values{
+		"filename": {avatarFilename(contentType)},
+		"size":     {strconv.Itoa(len(image))},
+	}
+	req, err := s.client.NewRequest("POST", "/rest/api/2/user/avatar/temporary"+query+"&"+upload.Encode(), bytes.NewReader(image), WithContentType(contentType))
+	if err != nil {
+		return "", nil, err
+	}
+	// Uploads are rejected by the XSRF check of JIRA without this header
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	cropping := new(avatarCropping)
+	resp, err := s.client.Do(req, cropping)
+	if err != nil {
+		return "", resp, err
+	}
+
+	req, err = s.client.NewRequest("POST", "/rest/api/2/user/avatar"+query, cropping)
+	if err != nil {
+		return "", nil, err
+	}
+
+	avatar := new(userAvatar)
+	resp, err = s.client.Do(req, avatar)
+	if err != nil {
+		return "", resp, err
+	}
+	return avatar.ID, resp, nil
+}
+
 // Search for users based on permissions in JIRA.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-findUsersWithAllPermissions
@@ -144,3 +456,155 @@ func (s *UserService) PermissionSearch(search UserPermissionSearch) (*[]User, *R
 	}
 	return &users, resp, nil
 }
+
+// findPageSize is the number of users Find requests per page
+var findPageSize = 50
+
+// AssignableWithProject restricts UserService.Find to users assignable to issues of the project, for a given project key.
+func AssignableWithProject(projectKey string) func(*url.Values) {
+	return func(v *url.Values) {
+		v.Set("project", projectKey)
+	}
+}
+
+// AssignableWithIssueKey restricts UserService.Find to users assignable to the issue, for a given issue key.
+func AssignableWithIssueKey(issueKey string) func(*url.Values) {
+	return func(v *url.Values) {
+		v.Set("issueKey", issueKey)
+	}
+}
+
+// Find returns all users matching query that can be assigned to issues, paging through all results.
+// Either AssignableWithProject or AssignableWithIssueKey has to be given to select the project or issue
+// the users are assignable to, otherwise an error is returned.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-findAssignableUsers
+func (s *UserService) Find(query string, opts ...func(*url.Values)) ([]User, error) {
+	v := url.Values{}
+	v.Set("query", query)
+	for _, opt := range opts {
+		opt(&v)
+	}
+	if v.Get("project") == "" && v.Get("issueKey") == "" {
+		return nil, fmt.Errorf("No project or issue key given")
+	}
+	v.Set("maxResults", strconv.Itoa(findPageSize))
+
+	users := []User{}
+	for {
+		v.Set("startAt", strconv.Itoa(len(users)))
+		req, err := s.client.NewRequest("GET", "/rest/api/2/user/assignable/search?"+v.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []User
+		if _, err := s.client.Do(req, &page); err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+		if len(page) < findPageSize {
+			return users, nil
+		}
+	}
+}
+
+// Picker returns users matching query in the format of the user picker, e.g. to feed a typeahead widget.
+// The query is matched against the user name, display name and email address.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-findUsersForPicker
+func (s *UserService) Picker(query string, options *PickerOptions) ([]PickerUser, *Response, error) {
+	params := struct {
+		Query string `url:"query"`
+		PickerOptions
+	}{Query: query}
+	if options != nil {
+		params.PickerOptions = *options
+	}
+
+	apiEndpoint, err := addOptions("/rest/api/2/user/picker", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(pickerResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.Users, resp, nil
+}
+
+// maxBulkUsers is the maximum number of users requested by a single bulk request
+const maxBulkUsers = 50
+
+// getBulk returns the users with the given account Ids. Account Ids are requested in batches of maxBulkUsers.
+//
+// JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-bulkGetUsers
+func (s *UserService) getBulk(accountIDs []string) ([]User, error) {
+	var users []User
+	for len(accountIDs) > 0 {
+		batch := accountIDs
+		if len(batch) > maxBulkUsers {
+			batch = batch[:maxBulkUsers]
+		}
+		accountIDs = accountIDs[len(batch):]
+
+		params := url.Values{"accountId": batch}
+		params.Set("maxResults", strconv.Itoa(len(batch)))
+		req, err := s.client.NewRequest("GET", "/rest/api/2/user/bulk?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []User
+		if _, err := s.client.DoPaged(req, &page, nil); err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+	}
+	return users, nil
+}
+
+// ResolveAssignees fills in the display name of every assignee of issues that only carries an account Id,
+// e.g. because not all user fields were requested. All unknown assignees are fetched with as few requests as possible.
+// The assignees of issues are updated in place.
+func (s *UserService) ResolveAssignees(issues []Issue) error {
+	var accountIDs []string
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Fields == nil || issue.Fields.Assignee == nil {
+			continue
+		}
+		assignee := issue.Fields.Assignee
+		if assignee.AccountID == "" || assignee.DisplayName != "" || seen[assignee.AccountID] {
+			continue
+		}
+		seen[assignee.AccountID] = true
+		accountIDs = append(accountIDs, assignee.AccountID)
+	}
+	if len(accountIDs) == 0 {
+		return nil
+	}
+
+	users, err := s.getBulk(accountIDs)
+	if err != nil {
+		return err
+	}
+	displayNames := make(map[string]string, len(users))
+	for _, user := range users {
+		displayNames[user.AccountID] = user.DisplayName
+	}
+
+	for _, issue := range issues {
+		if issue.Fields == nil || issue.Fields.Assignee == nil || issue.Fields.Assignee.DisplayName != "" {
+			continue
+		}
+		issue.Fields.Assignee.DisplayName = displayNames[issue.Fields.Assignee.AccountID]
+	}
+	return nil
+}
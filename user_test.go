@@ -1,8 +1,12 @@
 package jira
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,6 +33,28 @@ func TestUserService_Get_Success(t *testing.T) {
 	}
 }
 
+func TestUserService_GetByAccountID(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/user?accountId=557058%3Af58131cb-b67d-43c7-b30d-6b58d40bd077")
+		if r.URL.Query().Get("username") != "" {
+			t.Error("Expected no username parameter")
+		}
+
+		fmt.Fprint(w, `{"accountId":"557058:f58131cb-b67d-43c7-b30d-6b58d40bd077","displayName":"Fred F. User","active":true}`)
+	})
+
+	user, _, err := testClient.User.GetByAccountID("557058:f58131cb-b67d-43c7-b30d-6b58d40bd077")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if user.AccountID != "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077" || user.DisplayName != "Fred F. User" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+}
+
 func TestUserService_Create(t *testing.T) {
 	setup()
 	defer teardown()
@@ -55,3 +81,385 @@ func TestUserService_Create(t *testing.T) {
 		t.Error("Expected user. User is nil")
 	}
 }
+
+func TestUserService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, "/rest/api/2/user?accountId=5b10a2844c20165700ede21g")
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if want := `{"displayName":"Charlie Brown"}` + "\n"; string(body) != want {
+			t.Errorf("Expected only the set fields to be sent. Got %s", body)
+		}
+		fmt.Fprint(w, `{"accountId":"5b10a2844c20165700ede21g","name":"charlie","emailAddress":"charlie@atlassian.com",
+        "displayName":"Charlie Brown","active":true}`)
+	})
+
+	displayName := "Charlie Brown"
+	user, _, err := testClient.User.Update("5b10a2844c20165700ede21g", &UserUpdate{DisplayName: &displayName})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if user.DisplayName != "Charlie Brown" || user.EmailAddress != "charlie@atlassian.com" || !user.Active {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+}
+
+func TestUserService_Update_Deactivate(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if want := `{"active":false}` + "\n"; string(body) != want {
+			t.Errorf("Expected %s. Got %s", want, body)
+		}
+		fmt.Fprint(w, `{"accountId":"5b10a2844c20165700ede21g","displayName":"Charlie Brown","active":false}`)
+	})
+
+	active := false
+	user, _, err := testClient.User.Update("5b10a2844c20165700ede21g", &UserUpdate{Active: &active})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if user.Active {
+		t.Errorf("Expected an inactive user. Got %+v", user)
+	}
+}
+
+func TestUserService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, "/rest/api/2/user?username=charlie+brown%26co%2Fx%3Dy")
+		if got := r.URL.Query().Get("username"); got != "charlie brown&co/x=y" {
+			t.Errorf("Expected username %q. Got %q", "charlie brown&co/x=y", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := testClient.User.Delete("charlie brown&co/x=y")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d. Got %d", http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestUserService_Delete_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errorMessages":["The user named 'charlie' does not exist"],"errors":{}}`)
+	})
+
+	_, err := testClient.User.Delete("charlie")
+	notFound, ok := err.(*UserNotFoundError)
+	if !ok {
+		t.Fatalf("Expected *UserNotFoundError. Got %v", err)
+	}
+	if notFound.Username != "charlie" {
+		t.Errorf("Expected username charlie. Got %q", notFound.Username)
+	}
+	if notFound.Err.StatusCode != http.StatusNotFound || len(notFound.Err.ErrorMessages) != 1 {
+		t.Errorf("Expected the JIRA error to be kept. Got %+v", notFound.Err)
+	}
+}
+
+func TestUserService_Myself_Locale(t *testing.T) {
+	setup()
+	defer teardown()
+	raw, err := ioutil.ReadFile("./mocks/myself.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, string(raw))
+	})
+
+	user, _, err := testClient.User.Myself()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if user.Locale != "en_AU" {
+		t.Errorf("Expected locale en_AU. Got %s", user.Locale)
+	}
+	if user.Lang() != "en-AU" {
+		t.Errorf("Expected language en-AU. Got %s", user.Lang())
+	}
+}
+
+func TestUser_Lang(t *testing.T) {
+	for locale, want := range map[string]string{
+		"":      "",
+		"de":    "de",
+		"de_DE": "de-DE",
+		"pt_br": "pt-BR",
+		"EN-us": "en-US",
+	} {
+		u := &User{Locale: locale}
+		if got := u.Lang(); got != want {
+			t.Errorf("Lang of locale %q = %q. Expected %q", locale, got, want)
+		}
+	}
+}
+
+func TestUserService_MyselfWithExpand(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/myself?expand=groups%2CapplicationRoles")
+
+		fmt.Fprint(w, `{"self":"http://www.example.com/jira/rest/api/2/user?username=fred","key":"fred","name":"fred",
+        "displayName":"Fred F. User","active":true,"groups":{"size":2,"items":[
+        {"name":"jira-user","self":"http://www.example.com/jira/rest/api/2/group?groupname=jira-user"},
+        {"name":"jira-admin","self":"http://www.example.com/jira/rest/api/2/group?groupname=jira-admin"}]},
+        "applicationRoles":{"size":1,"items":[{"key":"jira-software","name":"JIRA Software"}]},"expand":"groups,applicationRoles"}`)
+	})
+
+	user, _, err := testClient.User.MyselfWithExpand("groups", "applicationRoles")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if user == nil {
+		t.Fatal("Expected user. User is nil")
+	}
+	if user.Groups == nil || len(user.Groups.Items) != 2 || user.Groups.Items[1].Name != "jira-admin" {
+		t.Errorf("Expected groups jira-user and jira-admin. Got %+v", user.Groups)
+	}
+	if user.ApplicationRoles == nil || len(user.ApplicationRoles.Items) != 1 || user.ApplicationRoles.Items[0].Key != "jira-software" {
+		t.Errorf("Expected application role jira-software. Got %+v", user.ApplicationRoles)
+	}
+}
+
+func TestUserService_Picker(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user/picker", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/user/picker?maxResults=10&query=fre&showAvatar=true")
+
+		fmt.Fprint(w, `{"users":[{"accountId":"5b10a2844c20165700ede21g","name":"fred","key":"fred",
+        "html":"<strong>Fre</strong>d F. User - fred@example.com (<strong>fre</strong>d)","displayName":"Fred F. User",
+        "avatarUrl":"http://www.example.com/jira/secure/useravatar?size=small&ownerId=fred"}],
+        "total":1,"header":"Showing 1 of 1 matching users"}`)
+	})
+
+	users, _, err := testClient.User.Picker("fre", &PickerOptions{MaxResults: 10, ShowAvatar: true})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("Expected 1 user. Got %d", len(users))
+	}
+	if users[0].AccountID != "5b10a2844c20165700ede21g" || users[0].HTML == "" || users[0].DisplayName != "Fred F. User" {
+		t.Errorf("Unexpected picker user: %+v", users[0])
+	}
+}
+
+func TestUserService_GetMyPermissions(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/mypermissions"
+
+	raw, err := ioutil.ReadFile("./mocks/my_permissions.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?permissions=BROWSE_PROJECTS%2CADMINISTER_PROJECTS&projectKey=TEST")
+		fmt.Fprint(w, string(raw))
+	})
+
+	permissions, _, err := testClient.User.GetMyPermissions("TEST", "BROWSE_PROJECTS", "ADMINISTER_PROJECTS")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(permissions) != 2 {
+		t.Fatalf("Expected 2 permissions. Got %d", len(permissions))
+	}
+	if !permissions["BROWSE_PROJECTS"].HavePermission {
+		t.Error("Expected BROWSE_PROJECTS permission")
+	}
+	if permissions["ADMINISTER_PROJECTS"].HavePermission {
+		t.Error("Expected no ADMINISTER_PROJECTS permission")
+	}
+}
+
+func TestUserService_ResolveAssignees(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/user/bulk"
+
+	calls := 0
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint+"?accountId=account-1&accountId=account-2&maxResults=2")
+		calls++
+		fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":2,"isLast":true,"values":[
+			{"accountId":"account-1","displayName":"Alice"},{"accountId":"account-2","displayName":"Bob"}]}`)
+	})
+
+	issues := []Issue{
+		{Key: "TEST-1", Fields: &IssueFields{Assignee: &User{AccountID: "account-1"}}},
+		{Key: "TEST-2", Fields: &IssueFields{Assignee: &User{AccountID: "account-2"}}},
+		{Key: "TEST-3", Fields: &IssueFields{Assignee: &User{AccountID: "account-1"}}},
+		{Key: "TEST-4", Fields: &IssueFields{}},
+	}
+	if err := testClient.User.ResolveAssignees(issues); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single bulk request. Got %d", calls)
+	}
+	for i, want := range []string{"Alice", "Bob", "Alice"} {
+		if got := issues[i].Fields.Assignee.DisplayName; got != want {
+			t.Errorf("Expected assignee %s for %s. Got %q", want, issues[i].Key, got)
+		}
+	}
+}
+
+func TestUserService_Find(t *testing.T) {
+	setup()
+	defer teardown()
+	defer func(size int) { findPageSize = size }(findPageSize)
+	findPageSize = 2
+	var startAts []string
+	testMux.HandleFunc("/rest/api/2/user/assignable/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		q := r.URL.Query()
+		if q.Get("query") != "fr" || q.Get("project") != "TEST" || q.Get("maxResults") != "2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		startAts = append(startAts, q.Get("startAt"))
+		switch q.Get("startAt") {
+		case "0":
+			fmt.Fprint(w, `[{"name":"fred"},{"name":"frank"}]`)
+		case "2":
+			fmt.Fprint(w, `[{"name":"frida"}]`)
+		default:
+			t.Errorf("Unexpected startAt %s", q.Get("startAt"))
+		}
+	})
+
+	users, err := testClient.User.Find("fr", AssignableWithProject("TEST"))
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	if want := []string{"fred", "frank", "frida"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected users %v. Got %v", want, names)
+	}
+	if want := []string{"0", "2"}; !reflect.DeepEqual(startAts, want) {
+		t.Errorf("Expected pages at %v. Got %v", want, startAts)
+	}
+}
+
+func TestUserService_Find_IssueKey(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user/assignable/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/user/assignable/search?issueKey=TEST-1&maxResults=50&query=fred&startAt=0")
+		fmt.Fprint(w, `[{"name":"fred"}]`)
+	})
+
+	users, err := testClient.User.Find("fred", AssignableWithIssueKey("TEST-1"))
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(users) != 1 || users[0].Name != "fred" {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+}
+
+func TestUserService_Find_NoProjectOrIssue(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user/assignable/search", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request")
+	})
+
+	if _, err := testClient.User.Find("fred"); err == nil {
+		t.Error("Expected an error. Got none")
+	}
+}
+
+func TestUserService_GetGroups(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/user?accountId=557058%3Af58131cb&expand=groups")
+		if expand := r.URL.Query()["expand"]; len(expand) != 1 {
+			t.Errorf("Expected expand exactly once. Got %v", expand)
+		}
+
+		fmt.Fprint(w, `{"accountId":"557058:f58131cb","groups":{"size":2,"items":[
+			{"name":"jira-administrators","self":"https://your-domain.atlassian.net/rest/api/2/group?groupname=jira-administrators"},
+			{"name":"jira-software-users","self":"https://your-domain.atlassian.net/rest/api/2/group?groupname=jira-software-users"}]}}`)
+	})
+
+	groups, _, err := testClient.User.GetGroups("557058:f58131cb")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if want := []string{"jira-administrators", "jira-software-users"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("Expected groups %v. Got %v", want, groups)
+	}
+}
+
+func TestUserService_SetAvatar(t *testing.T) {
+	setup()
+	defer teardown()
+	image := "\x89PNG\r\n\x1a\n"
+	testMux.HandleFunc("/rest/api/2/user/avatar/temporary", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/rest/api/2/user/avatar/temporary?accountId=557058%3Af58131cb&filename=avatar.png&size=8")
+		if got := r.Header.Get("Content-Type"); got != "image/png" {
+			t.Errorf("Expected Content-Type image/png. Got %s", got)
+		}
+		if got := r.Header.Get("X-Atlassian-Token"); got != "no-check" {
+			t.Errorf("Expected X-Atlassian-Token no-check. Got %s", got)
+		}
+		if body, _ := ioutil.ReadAll(r.Body); string(body) != image {
+			t.Errorf("Expected the image as body. Got %q", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"cropperWidth":120,"cropperOffsetX":50,"cropperOffsetY":50,"url":"https://your-domain.atlassian.net/secure/temporaryavatar?cropped=true","needsCropping":true}`)
+	})
+	testMux.HandleFunc("/rest/api/2/user/avatar", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, "/rest/api/2/user/avatar?accountId=557058%3Af58131cb")
+		var cropping avatarCropping
+		if err := json.NewDecoder(r.Body).Decode(&cropping); err != nil {
+			t.Fatalf("Error decoding the crop: %s", err)
+		}
+		if cropping.CropperWidth != 120 || cropping.CropperOffsetX != 50 || cropping.CropperOffsetY != 50 {
+			t.Errorf("Unexpected crop: %+v", cropping)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"1010","isSystemAvatar":false,"isSelected":false}`)
+	})
+
+	id, _, err := testClient.User.SetAvatar("557058:f58131cb", strings.NewReader(image), "image/png")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if id != "1010" {
+		t.Errorf("Expected avatar Id 1010. Got %s", id)
+	}
+}
@@ -1,9 +1,19 @@
 package jira
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
 )
 
 // WebhookService handles webhooks for the JIRA instance / API.
@@ -11,22 +21,235 @@ import (
 // JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/webhooks
 type WebhookService struct {
 	client *Client
+
+	// URLTestTimeout is the timeout of TestWebhookURL. Defaults to defaultURLTestTimeout.
+	URLTestTimeout time.Duration
+
+	// SkipEventValidation disables the check of Create that all events of a webhook are known WebhookEvent* events.
+	// Set it to register webhooks for events this library does not know about yet.
+	SkipEventValidation bool
+}
+
+// Events a webhook can be registered for.
+//
+// JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/webhooks#Webhooks-Events
+const (
+	WebhookEventIssueCreated              = "jira:issue_created"
+	WebhookEventIssueUpdated              = "jira:issue_updated"
+	WebhookEventIssueDeleted              = "jira:issue_deleted"
+	WebhookEventWorklogUpdated            = "jira:worklog_updated"
+	WebhookEventWorklogCreated            = "worklog_created"
+	WebhookEventWorklogDeleted            = "worklog_deleted"
+	WebhookEventIssuePropertySet          = "issue_property_set"
+	WebhookEventIssuePropertyDeleted      = "issue_property_deleted"
+	WebhookEventCommentCreated            = "comment_created"
+	WebhookEventCommentUpdated            = "comment_updated"
+	WebhookEventCommentDeleted            = "comment_deleted"
+	WebhookEventIssueLinkCreated          = "issuelink_created"
+	WebhookEventIssueLinkDeleted          = "issuelink_deleted"
+	WebhookEventProjectCreated            = "project_created"
+	WebhookEventProjectUpdated            = "project_updated"
+	WebhookEventProjectDeleted            = "project_deleted"
+	WebhookEventVersionCreated            = "jira:version_created"
+	WebhookEventVersionUpdated            = "jira:version_updated"
+	WebhookEventVersionDeleted            = "jira:version_deleted"
+	WebhookEventVersionMoved              = "jira:version_moved"
+	WebhookEventVersionReleased           = "jira:version_released"
+	WebhookEventVersionUnreleased         = "jira:version_unreleased"
+	WebhookEventUserCreated               = "user_created"
+	WebhookEventUserUpdated               = "user_updated"
+	WebhookEventUserDeleted               = "user_deleted"
+	WebhookEventSprintCreated             = "sprint_created"
+	WebhookEventSprintUpdated             = "sprint_updated"
+	WebhookEventSprintDeleted             = "sprint_deleted"
+	WebhookEventSprintStarted             = "sprint_started"
+	WebhookEventSprintClosed              = "sprint_closed"
+	WebhookEventBoardCreated              = "board_created"
+	WebhookEventBoardUpdated              = "board_updated"
+	WebhookEventBoardDeleted              = "board_deleted"
+	WebhookEventBoardConfigurationChanged = "board_configuration_changed"
+)
+
+// knownWebhookEvents contains all WebhookEvent* events
+var knownWebhookEvents = map[string]bool{
+	WebhookEventIssueCreated:              true,
+	WebhookEventIssueUpdated:              true,
+	WebhookEventIssueDeleted:              true,
+	WebhookEventWorklogUpdated:            true,
+	WebhookEventWorklogCreated:            true,
+	WebhookEventWorklogDeleted:            true,
+	WebhookEventIssuePropertySet:          true,
+	WebhookEventIssuePropertyDeleted:      true,
+	WebhookEventCommentCreated:            true,
+	WebhookEventCommentUpdated:            true,
+	WebhookEventCommentDeleted:            true,
+	WebhookEventIssueLinkCreated:          true,
+	WebhookEventIssueLinkDeleted:          true,
+	WebhookEventProjectCreated:            true,
+	WebhookEventProjectUpdated:            true,
+	WebhookEventProjectDeleted:            true,
+	WebhookEventVersionCreated:            true,
+	WebhookEventVersionUpdated:            true,
+	WebhookEventVersionDeleted:            true,
+	WebhookEventVersionMoved:              true,
+	WebhookEventVersionReleased:           true,
+	WebhookEventVersionUnreleased:         true,
+	WebhookEventUserCreated:               true,
+	WebhookEventUserUpdated:               true,
+	WebhookEventUserDeleted:               true,
+	WebhookEventSprintCreated:             true,
+	WebhookEventSprintUpdated:             true,
+	WebhookEventSprintDeleted:             true,
+	WebhookEventSprintStarted:             true,
+	WebhookEventSprintClosed:              true,
+	WebhookEventBoardCreated:              true,
+	WebhookEventBoardUpdated:              true,
+	WebhookEventBoardDeleted:              true,
+	WebhookEventBoardConfigurationChanged: true,
 }
 
+// validateEvents returns an error listing all events of webhook that are not known.
+func validateEvents(webhook *Webhook) error {
+	var unknown []string
+	for _, event := range webhook.Events {
+		if !knownWebhookEvents[event] {
+			unknown = append(unknown, fmt.Sprintf("%q", event))
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("Webhook %q has unknown events %s. Set SkipEventValidation to register them anyway", webhook.Name, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// defaultURLTestTimeout is the default timeout of WebhookService.TestWebhookURL
+const defaultURLTestTimeout = 5 * time.Second
+
 // Webhook represents a JIRA webhook.
+// ID, Self, Enabled and the LastUpdated* fields are read-only and only set on webhooks returned by JIRA.
+// The webhook API does not expose any delivery or failure information.
 type Webhook struct {
-	Name                string   `json:"name,omitempty" structs:"name,omitempty"`
-	Url                 string   `json:"url,omitempty" structs:"url,omitempty"`
-	Events              []string `json:"events,omitempty" structs:"events,omitempty"`
-	JqlFilter           string   `json:"jqlFilter,omitempty" structs:"jqlFilter,omitempty"`
-	ExcludeIssueDetails bool     `json:"excludeIssueDetails,omitempty" structs:"excludeIssueDetails,omitempty"`
+	// ID is not part of the API response, it is parsed from the end of Self
+	ID                     string   `json:"-" structs:"-"`
+	Name                   string   `json:"name,omitempty" structs:"name,omitempty"`
+	Url                    string   `json:"url,omitempty" structs:"url,omitempty"`
+	Events                 []string `json:"events,omitempty" structs:"events,omitempty"`
+	JqlFilter              string   `json:"jqlFilter,omitempty" structs:"jqlFilter,omitempty"`
+	ExcludeIssueDetails    bool     `json:"excludeIssueDetails,omitempty" structs:"excludeIssueDetails,omitempty"`
+	Self                   string   `json:"self,omitempty" structs:"self,omitempty"`
+	Enabled                bool     `json:"enabled,omitempty" structs:"enabled,omitempty"`
+	LastUpdated            int64    `json:"lastUpdated,omitempty" structs:"lastUpdated,omitempty"`
+	LastUpdatedUser        string   `json:"lastUpdatedUser,omitempty" structs:"lastUpdatedUser,omitempty"`
+	LastUpdatedDisplayName string   `json:"lastUpdatedDisplayName,omitempty" structs:"lastUpdatedDisplayName,omitempty"`
+	// Filters is returned by JIRA instead of JqlFilter, the JQL filter is its "issue-related-events-section"
+	Filters map[string]string `json:"filters,omitempty" structs:"filters,omitempty"`
+}
+
+// jqlFilter returns the JQL filter of the webhook, whether it was set by the caller or returned by JIRA.
+func (w *Webhook) jqlFilter() string {
+	if w.JqlFilter != "" {
+		return w.JqlFilter
+	}
+	return w.Filters["issue-related-events-section"]
+}
+
+// setID sets the ID of the webhook from its self link.
+func (w *Webhook) setID() {
+	if w.Self != "" {
+		w.ID = path.Base(w.Self)
+	}
+}
+
+// LastUpdatedTime returns the time the webhook was last updated.
+// JIRA reports it in milliseconds since the epoch; the zero time is returned if it is unknown.
+func (w *Webhook) LastUpdatedTime() time.Time {
+	return millisToTime(w.LastUpdated)
+}
+
+// WebhookEvent represents the payload JIRA posts to the URL of a webhook.
+// Issue and Changelog are only set for issue related events.
+type WebhookEvent struct {
+	WebhookEvent       string `json:"webhookEvent" structs:"webhookEvent"`
+	IssueEventTypeName string `json:"issue_event_type_name,omitempty" structs:"issue_event_type_name,omitempty"`
+	// Timestamp is the time of the event in milliseconds since the epoch, see Time
+	Timestamp int64             `json:"timestamp" structs:"timestamp"`
+	User      *User             `json:"user,omitempty" structs:"user,omitempty"`
+	Issue     *Issue            `json:"issue,omitempty" structs:"issue,omitempty"`
+	Changelog *WebhookChangelog `json:"changelog,omitempty" structs:"changelog,omitempty"`
+}
+
+// WebhookChangelog lists the changes of the issue that triggered a webhook event
+type WebhookChangelog struct {
+	ID    string           `json:"id" structs:"id"`
+	Items []ChangelogItems `json:"items" structs:"items"`
+}
+
+// Time returns the time of the event.
+func (e *WebhookEvent) Time() time.Time {
+	return millisToTime(e.Timestamp)
+}
+
+// ParseWebhookEvent reads the payload of a webhook callback from r, e.g. the body of the request JIRA sent to a webhook URL.
+func ParseWebhookEvent(r io.Reader) (*WebhookEvent, error) {
+	event := new(WebhookEvent)
+	if err := json.NewDecoder(r).Decode(event); err != nil {
+		return nil, fmt.Errorf("Could not parse webhook event: %s", err)
+	}
+	return event, nil
+}
+
+// WebhookSignatureHeader is the header JIRA sends the signature of a webhook delivery in
+const WebhookSignatureHeader = "X-Hub-Signature"
+
+// VerifyWebhookSignature reports whether signatureHeader is the hex encoded HMAC-SHA256 of body, keyed with secret.
+// An optional "sha256=" prefix of signatureHeader is ignored. Malformed signatures are reported as invalid.
+func VerifyWebhookSignature(secret string, body []byte, signatureHeader string) bool {
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signatureHeader), "sha256="))
+	if err != nil || len(signature) != sha256.Size {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// VerifyWebhookSignatureHandler wraps next to only handle webhook deliveries with a valid WebhookSignatureHeader,
+// see VerifyWebhookSignature. Other requests are answered with 401 Unauthorized.
+// The body of the request is still readable by next.
+func VerifyWebhookSignatureHandler(secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, "Could not read the request body", http.StatusBadRequest)
+			return
+		}
+		if !VerifyWebhookSignature(secret, body, r.Header.Get(WebhookSignatureHeader)) {
+			http.Error(w, "Invalid webhook signature", http.StatusUnauthorized)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
 }
 
 // Create creates a webhook in JIRA.
+// Unless SkipEventValidation is set, an error is returned without sending a request if the webhook has unknown events.
 //
 // JIRA API docs: https://docs.atlassian.com/jira/REST/cloud/#api/2/user-createUser
 func (s *WebhookService) Create(webhook *Webhook) (*Webhook, *Response, error) {
+	if !s.SkipEventValidation {
+		if err := validateEvents(webhook); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	apiEndpoint := "/rest/webhooks/1.0/webhook"
+	if webhook.JqlFilter != "" {
+		w := *webhook
+		w.JqlFilter = s.client.normalizeJQL(w.JqlFilter)
+		webhook = &w
+	}
 	req, err := s.client.NewRequest("POST", apiEndpoint, webhook)
 	if err != nil {
 		return nil, nil, err
@@ -47,9 +270,41 @@ func (s *WebhookService) Create(webhook *Webhook) (*Webhook, *Response, error) {
 	if err != nil {
 		return nil, resp, fmt.Errorf("Could not unmarshall the data into struct")
 	}
+	responseWebhook.setID()
 	return responseWebhook, resp, nil
 }
 
+// GetByID returns a single webhook.
+//
+// JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/webhooks#Webhooks-Registeringawebhook
+func (s *WebhookService) GetByID(webhookID string) (*Webhook, *Response, error) {
+	apiEndpoint := fmt.Sprintf("/rest/webhooks/1.0/webhook/%s", webhookID)
+	req, err := s.client.NewRequest("GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	webhook := new(Webhook)
+	resp, err := s.client.Do(req, webhook)
+	if err != nil {
+		return nil, resp, err
+	}
+	webhook.setID()
+	return webhook, resp, nil
+}
+
+// Delete deletes a webhook.
+//
+// JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/webhooks#Webhooks-Deletingawebhook
+func (s *WebhookService) Delete(webhookID string) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("/rest/webhooks/1.0/webhook/%s", webhookID)
+	req, err := s.client.NewRequest("DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
+
 // Gets all webhooks on the JIRA instance.
 //
 // JIRA API docs: https://developer.atlassian.com/jiradev/jira-apis/webhooks#Webhooks-Registeringawebhook
@@ -75,5 +330,209 @@ func (s *WebhookService) GetAll() (*[]Webhook, *Response, error) {
 	if err != nil {
 		return nil, resp, fmt.Errorf("Could not unmarshall the data into struct")
 	}
+	for i := range responseWebhook {
+		responseWebhook[i].setID()
+	}
 	return &responseWebhook, resp, nil
 }
+
+// GetSubscribedEvents returns all events at least one webhook on the JIRA instance subscribes to, sorted and without duplicates.
+func (s *WebhookService) GetSubscribedEvents() ([]string, error) {
+	webhooks, _, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	events := []string{}
+	for _, webhook := range *webhooks {
+		for _, event := range webhook.Events {
+			if !seen[event] {
+				seen[event] = true
+				events = append(events, event)
+			}
+		}
+	}
+	sort.Strings(events)
+	return events, nil
+}
+
+// CreateWebhooks creates all given webhooks in JIRA, in order.
+// If a webhook can not be created, the webhooks created so far are deleted again on a best-effort basis
+// and an error describing the failed creation and any failed rollbacks is returned.
+// Unless SkipEventValidation is set, the events of all webhooks are checked before any webhook is created.
+func (s *WebhookService) CreateWebhooks(webhooks []*Webhook) ([]*Webhook, error) {
+	if !s.SkipEventValidation {
+		for _, webhook := range webhooks {
+			if err := validateEvents(webhook); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	created := make([]*Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		w, _, err := s.Create(webhook)
+		if err == nil {
+			created = append(created, w)
+			continue
+		}
+
+		var failed []string
+		for _, c := range created {
+			if _, rollbackErr := s.delete(c); rollbackErr != nil {
+				failed = append(failed, fmt.Sprintf("%q (%s)", c.Name, rollbackErr))
+			}
+		}
+		if len(failed) > 0 {
+			return nil, fmt.Errorf("Could not create webhook %q: %s. Rollback failed for %s", webhook.Name, err, strings.Join(failed, ", "))
+		}
+		return nil, fmt.Errorf("Could not create webhook %q: %s", webhook.Name, err)
+	}
+	return created, nil
+}
+
+// delete deletes a webhook returned by JIRA.
+func (s *WebhookService) delete(webhook *Webhook) (*Response, error) {
+	if webhook.ID == "" {
+		return nil, fmt.Errorf("Webhook %q has no Id", webhook.Name)
+	}
+	return s.Delete(webhook.ID)
+}
+
+// update updates a webhook, for a given webhook Id.
+func (s *WebhookService) update(webhookID string, webhook *Webhook) (*Response, error) {
+	if !s.SkipEventValidation {
+		if err := validateEvents(webhook); err != nil {
+			return nil, err
+		}
+	}
+
+	apiEndpoint := fmt.Sprintf("/rest/webhooks/1.0/webhook/%s", webhookID)
+	w := *webhook
+	w.JqlFilter = s.client.normalizeJQL(w.JqlFilter)
+	// ExcludeIssueDetails is sent even if false, so an update can turn it off again
+	payload := struct {
+		*Webhook
+		ExcludeIssueDetails bool `json:"excludeIssueDetails"`
+	}{&w, w.ExcludeIssueDetails}
+	req, err := s.client.NewRequest("PUT", apiEndpoint, &payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
+
+// ReconcileWebhooks converges the webhooks of the JIRA instance to desired. Webhooks are matched by their URL:
+// desired webhooks without a registered webhook are created, registered webhooks whose name, events, JQL filter
+// or ExcludeIssueDetails differ from the desired webhook are updated, and registered webhooks without a desired webhook are deleted.
+// The URLs of the created, updated and deleted webhooks are returned. If a change fails, the changes made so far
+// are returned together with the error.
+func (s *WebhookService) ReconcileWebhooks(desired []Webhook) (created, updated, deleted []string, err error) {
+	wanted := make(map[string]bool, len(desired))
+	for _, webhook := range desired {
+		if wanted[webhook.Url] {
+			return nil, nil, nil, fmt.Errorf("Webhook URL %s is desired more than once", webhook.Url)
+		}
+		wanted[webhook.Url] = true
+	}
+
+	webhooks, _, err := s.GetAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	registered := make(map[string]Webhook, len(*webhooks))
+	for _, webhook := range *webhooks {
+		registered[webhook.Url] = webhook
+	}
+
+	for i := range desired {
+		webhook := &desired[i]
+		current, ok := registered[webhook.Url]
+		if !ok {
+			if _, _, err := s.Create(webhook); err != nil {
+				return created, updated, deleted, fmt.Errorf("Could not create webhook %s: %s", webhook.Url, err)
+			}
+			created = append(created, webhook.Url)
+			continue
+		}
+		if s.upToDate(&current, webhook) {
+			continue
+		}
+		if _, err := s.update(current.ID, webhook); err != nil {
+			return created, updated, deleted, fmt.Errorf("Could not update webhook %s: %s", webhook.Url, err)
+		}
+		updated = append(updated, webhook.Url)
+	}
+
+	for _, webhook := range *webhooks {
+		if wanted[webhook.Url] {
+			continue
+		}
+		if _, err := s.delete(&webhook); err != nil {
+			return created, updated, deleted, fmt.Errorf("Could not delete webhook %s: %s", webhook.Url, err)
+		}
+		deleted = append(deleted, webhook.Url)
+	}
+	return created, updated, deleted, nil
+}
+
+// upToDate reports whether the registered webhook has the name, events, JQL filter and ExcludeIssueDetails
+// of the desired webhook.
+func (s *WebhookService) upToDate(registered, desired *Webhook) bool {
+	if registered.Name != desired.Name || registered.jqlFilter() != s.client.normalizeJQL(desired.jqlFilter()) {
+		return false
+	}
+	if registered.ExcludeIssueDetails != desired.ExcludeIssueDetails {
+		return false
+	}
+	if len(registered.Events) != len(desired.Events) {
+		return false
+	}
+	events := make(map[string]bool, len(registered.Events))
+	for _, event := range registered.Events {
+		events[event] = true
+	}
+	for _, event := range desired.Events {
+		if !events[event] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestWebhookURL checks that url responds to requests before it is registered as a webhook.
+// A HEAD request is sent, falling back to OPTIONS if HEAD is not allowed. The request is sent without the
+// authentication of the JIRA client and has to be answered within URLTestTimeout.
+// Endpoints answering with 405 Method Not Allowed to both are considered reachable, as they may only accept POST.
+// An error is returned if url can not be reached or answers with any other client or server error.
+func (s *WebhookService) TestWebhookURL(url string) error {
+	timeout := s.URLTestTimeout
+	if timeout <= 0 {
+		timeout = defaultURLTestTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	var status int
+	for _, method := range []string{"HEAD", "OPTIONS"} {
+		req, err := http.NewRequest(method, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("Webhook URL %s is not reachable: %s", url, err)
+		}
+		resp.Body.Close()
+
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed {
+			break
+		}
+	}
+
+	if status >= 400 && status != http.StatusMethodNotAllowed {
+		return fmt.Errorf("Webhook URL %s responded with %d %s", url, status, http.StatusText(status))
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package jira
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookService_GetAll(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/webhooks/1.0/webhook"
+
+	raw, err := ioutil.ReadFile("./mocks/webhooks.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, string(raw))
+	})
+
+	webhooks, _, err := testClient.Webhook.GetAll()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if webhooks == nil {
+		t.Fatal("Expected webhook list. Webhook list is nil")
+	}
+	if len(*webhooks) != 2 {
+		t.Fatalf("Expected 2 webhooks. Got %d", len(*webhooks))
+	}
+
+	webhook := (*webhooks)[0]
+	if !webhook.Enabled {
+		t.Error("Expected first webhook to be enabled")
+	}
+	if webhook.Self != "https://jira.example.com/rest/webhooks/1.0/webhook/1" {
+		t.Errorf("Unexpected self: %s", webhook.Self)
+	}
+	if webhook.ID != "1" {
+		t.Errorf("Expected ID 1. Got %s", webhook.ID)
+	}
+	if webhook.LastUpdatedUser != "admin" || webhook.LastUpdatedDisplayName != "Administrator" {
+		t.Errorf("Unexpected last updated user: %s (%s)", webhook.LastUpdatedUser, webhook.LastUpdatedDisplayName)
+	}
+	if want := time.Date(2017, 4, 4, 17, 8, 54, 963000000, time.UTC); !webhook.LastUpdatedTime().Equal(want) {
+		t.Errorf("Expected last updated %s. Got %s", want, webhook.LastUpdatedTime())
+	}
+	if (*webhooks)[1].Enabled {
+		t.Error("Expected second webhook to be disabled")
+	}
+}
+
+func TestWebhookService_CreateWebhooks_RollbackOnFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	created := 0
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		created++
+		if created == 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"hook %d","self":"https://jira.example.com/rest/webhooks/1.0/webhook/%d"}`, created, created)
+	})
+	var deleted []string
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	webhooks := []*Webhook{{Name: "hook 1"}, {Name: "hook 2"}, {Name: "hook 3"}}
+	result, err := testClient.Webhook.CreateWebhooks(webhooks)
+	if err == nil {
+		t.Error("Expected an error. Got none")
+	}
+	if result != nil {
+		t.Errorf("Expected no webhooks. Got %v", result)
+	}
+	want := []string{"/rest/webhooks/1.0/webhook/1", "/rest/webhooks/1.0/webhook/2"}
+	if !reflect.DeepEqual(deleted, want) {
+		t.Errorf("Expected deleted webhooks %v. Got %v", want, deleted)
+	}
+}
+
+func TestWebhookService_TestWebhookURL(t *testing.T) {
+	var methods []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == "HEAD" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer target.Close()
+
+	webhooks := &WebhookService{}
+	if err := webhooks.TestWebhookURL(target.URL); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if want := []string{"HEAD", "OPTIONS"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("Expected requests %v. Got %v", want, methods)
+	}
+}
+
+func TestWebhookService_TestWebhookURL_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer target.Close()
+	defer close(done)
+
+	webhooks := &WebhookService{URLTestTimeout: 20 * time.Millisecond}
+	if err := webhooks.TestWebhookURL(target.URL); err == nil {
+		t.Error("Expected a timeout error. Got none")
+	}
+}
+
+func TestWebhookService_GetSubscribedEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"name":"one","events":["jira:issue_updated","jira:issue_created"]},
+			{"name":"two","events":["sprint_started","jira:issue_created"]},
+			{"name":"three","events":[]}]`)
+	})
+
+	events, err := testClient.Webhook.GetSubscribedEvents()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	want := []string{"jira:issue_created", "jira:issue_updated", "sprint_started"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Expected events %v. Got %v", want, events)
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	f, err := os.Open("./mocks/webhook_issue_updated.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer f.Close()
+
+	event, err := ParseWebhookEvent(f)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if event.WebhookEvent != "jira:issue_updated" || event.IssueEventTypeName != "issue_generic" {
+		t.Errorf("Unexpected event type: %s (%s)", event.WebhookEvent, event.IssueEventTypeName)
+	}
+	if want := time.Date(2017, 4, 4, 17, 8, 54, 963000000, time.UTC); !event.Time().Equal(want) {
+		t.Errorf("Expected event time %s. Got %s", want, event.Time())
+	}
+	if event.User == nil || event.User.Name != "admin" {
+		t.Errorf("Unexpected user: %+v", event.User)
+	}
+	if event.Issue == nil || event.Issue.Key != "TEST-1" || event.Issue.Fields.Status.Name != "In Progress" {
+		t.Errorf("Unexpected issue: %+v", event.Issue)
+	}
+	if event.Changelog == nil || len(event.Changelog.Items) != 1 {
+		t.Fatalf("Expected 1 changelog item. Got %+v", event.Changelog)
+	}
+	if item := event.Changelog.Items[0]; item.Field != "status" || item.FromString != "To Do" || item.ToString != "In Progress" {
+		t.Errorf("Unexpected changelog item: %+v", item)
+	}
+
+	if _, err := ParseWebhookEvent(strings.NewReader("{")); err == nil {
+		t.Error("Expected an error for an invalid payload. Got none")
+	}
+}
+
+func TestWebhookService_GetByID(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/webhooks/1.0/webhook/2"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"name":"Sprint events","url":"https://www.example.com/sprints","events":["sprint_started"],"self":"https://jira.example.com/rest/webhooks/1.0/webhook/2"}`)
+	})
+
+	webhook, _, err := testClient.Webhook.GetByID("2")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if webhook.ID != "2" || webhook.Name != "Sprint events" {
+		t.Errorf("Unexpected webhook: %+v", webhook)
+	}
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/webhooks/1.0/webhook/2"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, testAPIEndpoint)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := testClient.Webhook.Delete("2")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d. Got %d", http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestWebhookService_Create_ValidEvents(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"name":"Issue updates","self":"https://jira.example.com/rest/webhooks/1.0/webhook/1"}`)
+	})
+
+	webhook := &Webhook{Name: "Issue updates", Events: []string{WebhookEventIssueCreated, WebhookEventIssueUpdated}}
+	created, _, err := testClient.Webhook.Create(webhook)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if created.ID != "1" {
+		t.Errorf("Expected ID 1. Got %s", created.ID)
+	}
+}
+
+func TestWebhookService_Create_InvalidEvents(t *testing.T) {
+	setup()
+	defer teardown()
+	calls := 0
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"Issue updates","self":"https://jira.example.com/rest/webhooks/1.0/webhook/1"}`)
+	})
+
+	webhook := &Webhook{Name: "Issue updates", Events: []string{WebhookEventIssueCreated, "jira:issue_updatd"}}
+	_, _, err := testClient.Webhook.Create(webhook)
+	if err == nil {
+		t.Fatal("Expected an error. Got none")
+	}
+	if !strings.Contains(err.Error(), `"jira:issue_updatd"`) {
+		t.Errorf("Expected the unknown event in the error. Got %s", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request to be sent. Got %d", calls)
+	}
+
+	testClient.Webhook.SkipEventValidation = true
+	if _, _, err := testClient.Webhook.Create(webhook); err != nil {
+		t.Errorf("Expected validation to be skipped. Got %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request. Got %d", calls)
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"webhookEvent":"jira:issue_updated"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	for header, want := range map[string]bool{
+		signature:             true,
+		"sha256=" + signature: true,
+		"":                    false,
+		"sha256=":             false,
+		"sha256=not-hex":      false,
+		signature[:10]:        false,
+	} {
+		if got := VerifyWebhookSignature("secret", body, header); got != want {
+			t.Errorf("VerifyWebhookSignature(%q) = %v. Expected %v", header, got, want)
+		}
+	}
+	if VerifyWebhookSignature("other", body, signature) {
+		t.Error("Expected a signature with a different secret to be invalid")
+	}
+}
+
+func TestVerifyWebhookSignatureHandler(t *testing.T) {
+	body := `{"webhookEvent":"jira:issue_updated"}`
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var received string
+	handler := VerifyWebhookSignatureHandler("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		received = string(data)
+	}))
+
+	r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	r.Header.Set(WebhookSignatureHeader, signature)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || received != body {
+		t.Errorf("Expected the delivery to be handled. Got status %d and body %q", w.Code, received)
+	}
+
+	received = ""
+	r = httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	r.Header.Set(WebhookSignatureHeader, "sha256=forged")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || received != "" {
+		t.Errorf("Expected a forged delivery to be rejected. Got status %d", w.Code)
+	}
+}
+
+func TestWebhookService_ReconcileWebhooks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests []string
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `[
+				{"name":"Unchanged","url":"https://www.example.com/unchanged","events":["jira:issue_updated","jira:issue_created"],
+				 "filters":{"issue-related-events-section":"project = TEST"},"self":"https://jira.example.com/rest/webhooks/1.0/webhook/1"},
+				{"name":"Changed","url":"https://www.example.com/changed","events":["jira:issue_created"],
+				 "self":"https://jira.example.com/rest/webhooks/1.0/webhook/2"},
+				{"name":"Stale","url":"https://www.example.com/stale","events":["sprint_started"],
+				 "self":"https://jira.example.com/rest/webhooks/1.0/webhook/3"},
+				{"name":"Details","url":"https://www.example.com/details","events":["jira:issue_created"],"excludeIssueDetails":true,
+				 "self":"https://jira.example.com/rest/webhooks/1.0/webhook/5"}]`)
+		case "POST":
+			fmt.Fprint(w, `{"name":"New","url":"https://www.example.com/new","self":"https://jira.example.com/rest/webhooks/1.0/webhook/4"}`)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/rest/webhooks/1.0/webhook/5" {
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if exclude, ok := payload["excludeIssueDetails"]; !ok || exclude != false {
+				t.Errorf("Expected excludeIssueDetails to be turned off. Got %v", payload)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	desired := []Webhook{
+		{Name: "Unchanged", Url: "https://www.example.com/unchanged", Events: []string{WebhookEventIssueCreated, WebhookEventIssueUpdated}, JqlFilter: " project = TEST "},
+		{Name: "Changed", Url: "https://www.example.com/changed", Events: []string{WebhookEventIssueCreated, WebhookEventIssueDeleted}},
+		{Name: "New", Url: "https://www.example.com/new", Events: []string{WebhookEventSprintStarted}},
+		{Name: "Details", Url: "https://www.example.com/details", Events: []string{WebhookEventIssueCreated}},
+	}
+	created, updated, deleted, err := testClient.Webhook.ReconcileWebhooks(desired)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if want := []string{"https://www.example.com/new"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("Expected created %v. Got %v", want, created)
+	}
+	if want := []string{"https://www.example.com/changed", "https://www.example.com/details"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("Expected updated %v. Got %v", want, updated)
+	}
+	if want := []string{"https://www.example.com/stale"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("Expected deleted %v. Got %v", want, deleted)
+	}
+	want := []string{
+		"GET /rest/webhooks/1.0/webhook",
+		"PUT /rest/webhooks/1.0/webhook/2",
+		"POST /rest/webhooks/1.0/webhook",
+		"PUT /rest/webhooks/1.0/webhook/5",
+		"DELETE /rest/webhooks/1.0/webhook/3",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Expected requests %v. Got %v", want, requests)
+	}
+}
+
+func TestWebhookService_CreateWebhooks_InvalidEvents(t *testing.T) {
+	setup()
+	defer teardown()
+	calls := 0
+	testMux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"Issue updates","self":"https://jira.example.com/rest/webhooks/1.0/webhook/1"}`)
+	})
+
+	webhooks := []*Webhook{
+		{Name: "Issue created", Events: []string{WebhookEventIssueCreated}},
+		{Name: "Issue updates", Events: []string{"jira:issue_updatd"}},
+	}
+	_, err := testClient.Webhook.CreateWebhooks(webhooks)
+	if err == nil || !strings.Contains(err.Error(), `"jira:issue_updatd"`) {
+		t.Errorf("Expected an error for the unknown event. Got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request to be sent. Got %d", calls)
+	}
+}